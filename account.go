@@ -1,5 +1,12 @@
 package gosyncengine
 
+const (
+	// OrganizationUnitFolder is the organization unit of accounts using folders
+	OrganizationUnitFolder = "folder"
+	// OrganizationUnitLabel is the organization unit of accounts using labels (such as Gmail)
+	OrganizationUnitLabel = "label"
+)
+
 // Account providers information on a single account defined on the sync engine
 type Account struct {
 	ID               string `json:"id"`
@@ -14,3 +21,17 @@ type Account struct {
 
 // Accounts is a typed array of Account objects
 type Accounts []Account
+
+// UsesLabels returns true if the account organizes messages with labels rather than folders
+func (a Account) UsesLabels() bool {
+	return a.OrganizationUnit == OrganizationUnitLabel
+}
+
+// apiID returns the ID used to authenticate calls on behalf of the account
+func (a Account) apiID() string {
+	if a.AccountID != "" {
+		return a.AccountID
+	}
+
+	return a.ID
+}
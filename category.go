@@ -0,0 +1,27 @@
+package gosyncengine
+
+// Category is either a folder or a label, depending on the organization unit of the account it belongs to
+type Category struct {
+	ID          string `json:"id"`
+	AccountID   string `json:"account_id"`
+	Object      string `json:"object"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// Categories is a collection of Category objects
+type Categories []Category
+
+// categoryRequest is the body of folder and label create and update calls
+type categoryRequest struct {
+	DisplayName string `json:"display_name"`
+}
+
+// categoryPath returns the API path of the folders or labels of the given account
+func categoryPath(account Account) string {
+	if account.UsesLabels() {
+		return "/labels"
+	}
+
+	return "/folders"
+}
@@ -0,0 +1,17 @@
+package gosyncengine
+
+// Contact contains the details of a single contact
+type Contact struct {
+	ID           string               `json:"id"`
+	AccountID    string               `json:"account_id"`
+	Object       string               `json:"object"`
+	Name         string               `json:"name"`
+	Email        string               `json:"email"`
+	PhoneNumbers []ContactPhoneNumber `json:"phone_numbers"`
+}
+
+// ContactPhoneNumber is a single phone number of a contact
+type ContactPhoneNumber struct {
+	Type   string `json:"type"`
+	Number string `json:"number"`
+}
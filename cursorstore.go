@@ -0,0 +1,131 @@
+package gosyncengine
+
+import (
+	"context"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CursorStore persists the delta cursor of each account so that delta consumers
+// can resume where they left off after a restart
+type CursorStore interface {
+	// Load returns the saved cursor of the account, or an empty string if none was saved
+	Load(accountID string) (string, error)
+	// Save stores the cursor of the account
+	Save(accountID string, cursor string) error
+}
+
+// MemoryCursorStore keeps cursors in memory
+type MemoryCursorStore struct {
+	mu      sync.Mutex
+	cursors map[string]string
+}
+
+// NewMemoryCursorStore creates an empty in-memory cursor store
+func NewMemoryCursorStore() *MemoryCursorStore {
+	return &MemoryCursorStore{
+		cursors: map[string]string{},
+	}
+}
+
+// Load returns the saved cursor of the account
+func (s *MemoryCursorStore) Load(accountID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cursors[accountID], nil
+}
+
+// Save stores the cursor of the account
+func (s *MemoryCursorStore) Save(accountID string, cursor string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursors[accountID] = cursor
+	return nil
+}
+
+// FileCursorStore keeps the cursor of each account in its own file in a directory.
+// Files are replaced atomically so a crash never leaves a partially written cursor.
+type FileCursorStore struct {
+	dir string
+}
+
+// NewFileCursorStore creates a cursor store in the given directory, creating it if needed
+func NewFileCursorStore(dir string) (*FileCursorStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	return &FileCursorStore{
+		dir: dir,
+	}, nil
+}
+
+func (s *FileCursorStore) path(accountID string) string {
+	return filepath.Join(s.dir, url.PathEscape(accountID)+".cursor")
+}
+
+// Load returns the saved cursor of the account
+func (s *FileCursorStore) Load(accountID string) (string, error) {
+	data, err := ioutil.ReadFile(s.path(accountID))
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// Save stores the cursor of the account
+func (s *FileCursorStore) Save(accountID string, cursor string) error {
+	file, err := ioutil.TempFile(s.dir, ".cursor-")
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.WriteString(cursor); err == nil {
+		err = file.Sync()
+	}
+
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(file.Name(), s.path(accountID))
+	}
+
+	if err != nil {
+		os.Remove(file.Name())
+	}
+
+	return err
+}
+
+// loadCursor returns the saved cursor of the account. Accounts without a saved cursor
+// start from the latest cursor, which is saved right away.
+func (api *SyncEngineAPI) loadCursor(ctx context.Context, accountID string, store CursorStore) (string, error) {
+	var cursor string
+	var err error
+
+	if cursor, err = store.Load(accountID); err != nil || cursor != "" {
+		return cursor, err
+	}
+
+	var latest *DeltaCursor
+	if latest, err = api.GetDeltaLatestCursorContext(ctx, accountID); err != nil {
+		return "", err
+	}
+
+	if err = store.Save(accountID, latest.Cursor); err != nil {
+		return "", err
+	}
+
+	return latest.Cursor, nil
+}
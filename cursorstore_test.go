@@ -0,0 +1,102 @@
+package gosyncengine
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestFileCursorStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gosyncengine")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileCursorStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cursor, err := store.Load("aaa"); err != nil || cursor != "" {
+		t.Errorf("Expected no cursor for a new account, got %q %v", cursor, err)
+	}
+
+	for _, cursor := range []string{"c1", "c2"} {
+		if err = store.Save("aaa", cursor); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A new store on the same directory sees the saved cursors
+	reopened, _ := NewFileCursorStore(dir)
+	if cursor, err := reopened.Load("aaa"); err != nil || cursor != "c2" {
+		t.Errorf("Expected cursor c2, got %q %v", cursor, err)
+	}
+
+	files, _ := ioutil.ReadDir(dir)
+	if len(files) != 1 {
+		t.Errorf("Expected a single cursor file, got %d files", len(files))
+	}
+}
+
+func TestDeltaIteratorFromStore(t *testing.T) {
+	server := newDeltaServer(t, map[string][]string{
+		"c0": {"c1", "c2"},
+		"c2": {"c3"},
+	})
+	defer server.Close()
+
+	client := New(server.URL)
+	store := NewMemoryCursorStore()
+	store.Save("zzz", "c0")
+
+	it, err := client.IterateDeltaFromStore(context.Background(), "zzz", store, DeltaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the first page is acknowledged, as if the consumer crashed handling the second one
+	if !it.Next() || it.Ack() != nil || !it.Next() {
+		t.Fatalf("Expected two pages, got %v", it.Err())
+	}
+
+	if cursor, _ := store.Load("zzz"); cursor != "c2" {
+		t.Fatalf("Expected the acknowledged cursor c2 to be saved, got %s", cursor)
+	}
+
+	it, err = client.IterateDeltaFromStore(context.Background(), "zzz", store, DeltaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !it.Next() || it.Delta().Deltas[0].ID != "c3" {
+		t.Errorf("Expected the unacknowledged page to be delivered again, got %v %v", it.Delta(), it.Err())
+	}
+}
+
+func TestDeltaIteratorFromEmptyStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/delta/latest_cursor" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"cursor": "latest"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	store := NewMemoryCursorStore()
+
+	it, err := client.IterateDeltaFromStore(context.Background(), "zzz", store, DeltaOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cursor, _ := store.Load("zzz"); cursor != "latest" || it.Cursor() != "latest" {
+		t.Errorf("Expected to start from the latest cursor, got %s", cursor)
+	}
+}
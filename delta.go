@@ -0,0 +1,157 @@
+package gosyncengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Delta event types
+const (
+	DeltaEventCreate = "create"
+	DeltaEventModify = "modify"
+	DeltaEventDelete = "delete"
+)
+
+// Object types reported by the delta API
+const (
+	ObjectMessage = "message"
+	ObjectThread  = "thread"
+	ObjectFolder  = "folder"
+	ObjectLabel   = "label"
+	ObjectDraft   = "draft"
+	ObjectContact = "contact"
+	ObjectEvent   = "event"
+	ObjectFile    = "file"
+)
+
+// Deltas contains a delta chunk of changes to objects of any type
+type Deltas struct {
+	CursorStart string  `json:"cursor_start"`
+	CursorEnd   string  `json:"cursor_end"`
+	Deltas      []Delta `json:"deltas"`
+}
+
+// Delta is a single change to an object
+type Delta struct {
+	Cursor string `json:"cursor"`
+	Event  string `json:"event"`
+	Object string `json:"object"`
+	ID     string `json:"id"`
+	// RawAttributes holds the attributes as returned by the sync engine. It is empty for deletes.
+	RawAttributes json.RawMessage `json:"attributes,omitempty"`
+	// Attributes holds the attributes decoded according to Object
+	Attributes DeltaAttributes `json:"-"`
+}
+
+// DeltaAttributes holds the decoded attributes of a delta. Only the field
+// matching the object type of the delta is set, and none is set for deletes
+// or for object types without a model.
+type DeltaAttributes struct {
+	Message *Message
+	Thread  *Thread
+	Folder  *Folder
+	Label   *Label
+	Draft   *Draft
+	Contact *Contact
+	Event   *Event
+	File    *File
+}
+
+// UnmarshalJSON decodes a delta and its attributes according to its object type
+func (d *Delta) UnmarshalJSON(data []byte) error {
+	// delta has the fields of Delta without its UnmarshalJSON method
+	type delta Delta
+
+	var raw delta
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*d = Delta(raw)
+	return d.Attributes.decode(d.Object, d.RawAttributes)
+}
+
+// IsDelete returns true if the delta reports a deleted object
+func (d Delta) IsDelete() bool {
+	return d.Event == DeltaEventDelete
+}
+
+func (a *DeltaAttributes) decode(object string, data json.RawMessage) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+
+	var target interface{}
+	switch object {
+	case ObjectMessage:
+		a.Message = &Message{}
+		target = a.Message
+	case ObjectThread:
+		a.Thread = &Thread{}
+		target = a.Thread
+	case ObjectFolder:
+		a.Folder = &Folder{}
+		target = a.Folder
+	case ObjectLabel:
+		a.Label = &Label{}
+		target = a.Label
+	case ObjectDraft:
+		a.Draft = &Draft{}
+		target = a.Draft
+	case ObjectContact:
+		a.Contact = &Contact{}
+		target = a.Contact
+	case ObjectEvent:
+		a.Event = &Event{}
+		target = a.Event
+	case ObjectFile:
+		a.File = &File{}
+		target = a.File
+	default:
+		return nil
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return fmt.Errorf("Delta %s attributes deserialization failed. Reason: %s", object, err)
+	}
+
+	return nil
+}
+
+// DeltaOptions controls which changes are returned by the delta API
+type DeltaOptions struct {
+	// IncludeTypes limits the deltas to the given object types. It cannot be combined with ExcludeTypes.
+	IncludeTypes []string
+	// ExcludeTypes filters out deltas of the given object types
+	ExcludeTypes []string
+	// Expanded requests the expanded view of objects (such as messages with their headers)
+	Expanded bool
+}
+
+// deltaMessagesOptions are the options used by GetDeltaMessages
+var deltaMessagesOptions = DeltaOptions{IncludeTypes: []string{ObjectMessage}, Expanded: true}
+
+func (o DeltaOptions) encode(cursor string) string {
+	return o.values(cursor).Encode()
+}
+
+func (o DeltaOptions) values(cursor string) url.Values {
+	values := url.Values{}
+	values.Set("cursor", cursor)
+
+	if len(o.IncludeTypes) > 0 {
+		values.Set("include_types", strings.Join(o.IncludeTypes, ","))
+	}
+
+	if len(o.ExcludeTypes) > 0 {
+		values.Set("exclude_types", strings.Join(o.ExcludeTypes, ","))
+	}
+
+	if o.Expanded {
+		values.Set("view", "expanded")
+	}
+
+	return values
+}
@@ -0,0 +1,248 @@
+package gosyncengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const testDeltas = `{
+    "cursor_start": "c0",
+    "cursor_end": "c4",
+    "deltas": [
+        {
+            "attributes": {
+                "account_id": "zzz",
+                "id": "mmm",
+                "object": "message",
+                "subject": "Hello",
+                "thread_id": "ttt"
+            },
+            "cursor": "c1",
+            "event": "create",
+            "id": "mmm",
+            "object": "message"
+        },
+        {
+            "attributes": {
+                "account_id": "zzz",
+                "id": "ttt",
+                "object": "thread",
+                "subject": "Hello",
+                "unread": true
+            },
+            "cursor": "c2",
+            "event": "modify",
+            "id": "ttt",
+            "object": "thread"
+        },
+        {
+            "attributes": {
+                "display_name": "Receipts",
+                "id": "fff",
+                "object": "folder"
+            },
+            "cursor": "c3",
+            "event": "create",
+            "id": "fff",
+            "object": "folder"
+        },
+        {
+            "cursor": "c4",
+            "event": "delete",
+            "id": "mm2",
+            "object": "message"
+        }
+    ]
+}`
+
+func TestGetDelta(t *testing.T) {
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/delta" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		received = r.URL.Query()
+		w.Write([]byte(testDeltas))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	deltas, err := client.GetDelta("zzz", "c0", DeltaOptions{ExcludeTypes: []string{ObjectContact, ObjectEvent}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if received.Get("cursor") != "c0" || received.Get("exclude_types") != "contact,event" || received.Get("include_types") != "" {
+		t.Errorf("Unexpected GetDelta request: %v", received)
+	}
+
+	if deltas.CursorEnd != "c4" || len(deltas.Deltas) != 4 {
+		t.Fatalf("Unexpected GetDelta result: %v", deltas)
+	}
+
+	if message := deltas.Deltas[0].Attributes.Message; message == nil || message.Subject != "Hello" || deltas.Deltas[0].Attributes.Thread != nil {
+		t.Errorf("Unexpected message attributes: %v", deltas.Deltas[0].Attributes)
+	}
+
+	if thread := deltas.Deltas[1].Attributes.Thread; thread == nil || !thread.Unread {
+		t.Errorf("Unexpected thread attributes: %v", deltas.Deltas[1].Attributes)
+	}
+
+	if folder := deltas.Deltas[2].Attributes.Folder; folder == nil || folder.DisplayName != "Receipts" {
+		t.Errorf("Unexpected folder attributes: %v", deltas.Deltas[2].Attributes)
+	}
+
+	if deleted := deltas.Deltas[3]; !deleted.IsDelete() || deleted.ID != "mm2" || deleted.Attributes.Message != nil {
+		t.Errorf("Unexpected delete delta: %v", deleted)
+	}
+}
+
+func TestDeltaRoundTrip(t *testing.T) {
+	var deltas Deltas
+	if err := json.Unmarshal([]byte(testDeltas), &deltas); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(deltas)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Deltas
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Deltas[0].Attributes.Message == nil || decoded.Deltas[0].Attributes.Message.ID != "mmm" {
+		t.Errorf("Unexpected round tripped delta: %v", decoded.Deltas[0])
+	}
+}
+
+func TestGetDeltaMessagesDeletes(t *testing.T) {
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query()
+		w.Write([]byte(`{
+    "cursor_start": "c0",
+    "cursor_end": "c2",
+    "deltas": [
+        {
+            "attributes": {"id": "mmm", "object": "message", "subject": "Hello"},
+            "cursor": "c1",
+            "event": "modify",
+            "id": "mmm",
+            "object": "message"
+        },
+        {
+            "cursor": "c2",
+            "event": "delete",
+            "id": "mm2",
+            "object": "message"
+        }
+    ]
+}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	deltas, err := client.GetDeltaMessages("zzz", "c0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if received.Get("include_types") != "message" || received.Get("view") != "expanded" {
+		t.Errorf("Unexpected GetDeltaMessages request: %v", received)
+	}
+
+	if len(deltas.Deltas) != 2 {
+		t.Fatalf("Unexpected GetDeltaMessages result: %v", deltas)
+	}
+
+	if modified := deltas.Deltas[0]; modified.IsDelete() || modified.Event != DeltaEventModify || modified.Attributes.Subject != "Hello" {
+		t.Errorf("Unexpected modify delta: %v", modified)
+	}
+
+	if deleted := deltas.Deltas[1]; !deleted.IsDelete() || deleted.ID != "mm2" || deleted.Cursor != "c2" {
+		t.Errorf("Unexpected delete delta: %v", deleted)
+	}
+}
+
+func TestLongPollDelta(t *testing.T) {
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/delta/longpoll" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		received = r.URL.Query()
+
+		// The sync engine keeps the connection alive with whitespace until changes are available
+		w.Write([]byte(" "))
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(testDeltas))
+	}))
+	defer server.Close()
+
+	// The client timeout must not cut long polls short
+	client := New(server.URL, WithTimeout(50*time.Millisecond))
+
+	deltas, err := client.LongPollDelta(context.Background(), "zzz", "c0", 30*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if received.Get("cursor") != "c0" || received.Get("timeout") != "30" {
+		t.Errorf("Unexpected LongPollDelta request: %v", received)
+	}
+
+	if deltas.CursorEnd != "c4" || len(deltas.Deltas) != 4 {
+		t.Errorf("Unexpected LongPollDelta result: %v", deltas)
+	}
+}
+
+func TestLongPollDeltaDeadline(t *testing.T) {
+	received := make(chan url.Values, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.URL.Query()
+
+		// Stall well past the requested timeout
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	gracePeriod := longPollGracePeriod
+	longPollGracePeriod = 100 * time.Millisecond
+	defer func() {
+		longPollGracePeriod = gracePeriod
+	}()
+
+	client := New(server.URL)
+
+	start := time.Now()
+	_, err := client.LongPollDelta(context.Background(), "zzz", "c0", 200*time.Millisecond)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the long poll to hit its deadline, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Long poll took %v", elapsed)
+	}
+
+	// Sub-second timeouts are rounded up rather than sent as 0
+	if query := <-received; query.Get("timeout") != "1" {
+		t.Errorf("Unexpected LongPollDelta request: %v", query)
+	}
+}
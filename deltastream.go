@@ -0,0 +1,231 @@
+package gosyncengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// StreamOptions controls a delta stream
+type StreamOptions struct {
+	DeltaOptions
+	// Timeout is the duration after which the sync engine ends the stream, which is then reconnected.
+	// Zero uses the sync engine's default.
+	Timeout time.Duration
+	// ReconnectDelay is the delay before reconnecting after a failure (defaults to 1 second).
+	// It doubles on consecutive failures, up to MaxReconnectDelay (defaults to 1 minute).
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+	// BufferSize is the capacity of the deltas channel
+	BufferSize int
+	// OnDisconnect, if set, is called every time the stream disconnects. The error is nil
+	// if the sync engine ended the stream normally.
+	OnDisconnect func(err error)
+}
+
+// DeltaStream delivers the deltas read from /delta/streaming, reconnecting from the
+// cursor of the last delivered delta whenever the connection ends
+type DeltaStream struct {
+	api       *SyncEngineAPI
+	accountID string
+	options   StreamOptions
+	store     CursorStore
+	deltas    chan Delta
+
+	mu     sync.Mutex
+	cursor string
+	err    error
+}
+
+// StreamDelta opens a delta stream starting after the given cursor. The stream runs until the context
+// is canceled or a non retryable error occurs, at which point the Deltas channel is closed.
+func (api *SyncEngineAPI) StreamDelta(ctx context.Context, accountID string, cursor string, options StreamOptions) *DeltaStream {
+	return api.startDeltaStream(ctx, accountID, cursor, nil, options)
+}
+
+func (api *SyncEngineAPI) startDeltaStream(ctx context.Context, accountID string, cursor string, store CursorStore, options StreamOptions) *DeltaStream {
+	if options.ReconnectDelay <= 0 {
+		options.ReconnectDelay = time.Second
+	}
+
+	if options.MaxReconnectDelay <= 0 {
+		options.MaxReconnectDelay = time.Minute
+	}
+
+	stream := &DeltaStream{
+		api:       api,
+		accountID: accountID,
+		options:   options,
+		store:     store,
+		deltas:    make(chan Delta, options.BufferSize),
+		cursor:    cursor,
+	}
+
+	go stream.run(ctx)
+
+	return stream
+}
+
+// StreamDeltaFromStore opens a delta stream starting after the cursor saved in store (or the latest cursor
+// if none was saved). Deltas are checkpointed only when acknowledged with Ack.
+func (api *SyncEngineAPI) StreamDeltaFromStore(ctx context.Context, accountID string, store CursorStore, options StreamOptions) (*DeltaStream, error) {
+	var cursor string
+	var err error
+
+	if cursor, err = api.loadCursor(ctx, accountID, store); err != nil {
+		return nil, err
+	}
+
+	return api.startDeltaStream(ctx, accountID, cursor, store, options), nil
+}
+
+// Ack checkpoints the cursor of a delta received from the stream in the stream's cursor store, once the delta
+// (and all the ones before it) was handled. It does nothing for streams created without a cursor store.
+func (s *DeltaStream) Ack(delta Delta) error {
+	if s.store == nil || delta.Cursor == "" {
+		return nil
+	}
+
+	return s.store.Save(s.accountID, delta.Cursor)
+}
+
+// Deltas returns the channel the deltas are delivered on. It is closed when the stream stops.
+func (s *DeltaStream) Deltas() <-chan Delta {
+	return s.deltas
+}
+
+// Cursor returns the cursor of the last delta delivered on the channel
+func (s *DeltaStream) Cursor() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.cursor
+}
+
+// Err returns the error that stopped the stream once the Deltas channel is closed.
+// It is the context's error if the stream was stopped by its context.
+func (s *DeltaStream) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.err
+}
+
+func (s *DeltaStream) run(ctx context.Context) {
+	defer close(s.deltas)
+
+	var failures int
+	for {
+		received, err := s.connect(ctx)
+
+		if ctx.Err() != nil {
+			s.stop(ctx.Err())
+			return
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !isRetryableStatus(apiErr.StatusCode) {
+			s.stop(err)
+			return
+		}
+
+		if s.options.OnDisconnect != nil {
+			s.options.OnDisconnect(err)
+		}
+
+		if received > 0 {
+			failures = 0
+
+			// The connection made progress, so a clean end resumes right away from the last cursor
+			if err == nil {
+				continue
+			}
+		}
+
+		failures++
+		if err = sleepContext(ctx, s.reconnectDelay(failures)); err != nil {
+			s.stop(err)
+			return
+		}
+	}
+}
+
+func (s *DeltaStream) reconnectDelay(failures int) time.Duration {
+	delay := s.options.ReconnectDelay
+	for i := 1; i < failures && delay < s.options.MaxReconnectDelay; i++ {
+		delay *= 2
+	}
+
+	if delay > s.options.MaxReconnectDelay {
+		delay = s.options.MaxReconnectDelay
+	}
+
+	return delay
+}
+
+// connect reads deltas from a single streaming connection until it ends and returns the number of deltas delivered
+func (s *DeltaStream) connect(ctx context.Context) (int, error) {
+	var resp *http.Response
+	var err error
+
+	var values = s.options.values(s.Cursor())
+	if s.options.Timeout > 0 {
+		values.Set("timeout", strconv.Itoa(timeoutSeconds(s.options.Timeout)))
+	}
+
+	var request = apiRequest{
+		method:    http.MethodGet,
+		userID:    s.accountID,
+		path:      withQuery("/delta/streaming", values.Encode()),
+		longLived: true,
+	}
+
+	if resp, err = s.api.do(ctx, request); err != nil {
+		return 0, err
+	}
+
+	if !isSuccessStatus(resp.StatusCode) {
+		return 0, decodeResponse(resp, nil)
+	}
+
+	defer resp.Body.Close()
+
+	// The sync engine sends concatenated deltas separated by whitespace heartbeats
+	var received int
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var delta Delta
+		if err = decoder.Decode(&delta); err == io.EOF {
+			return received, nil
+		} else if err != nil {
+			return received, err
+		}
+
+		select {
+		case s.deltas <- delta:
+			received++
+			s.setCursor(delta.Cursor)
+		case <-ctx.Done():
+			return received, ctx.Err()
+		}
+	}
+}
+
+func (s *DeltaStream) setCursor(cursor string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cursor = cursor
+}
+
+func (s *DeltaStream) stop(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
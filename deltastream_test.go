@@ -0,0 +1,172 @@
+package gosyncengine
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStreamDeltaReconnects(t *testing.T) {
+	var mu sync.Mutex
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/delta/streaming" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		mu.Lock()
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		connection := len(cursors)
+		mu.Unlock()
+
+		switch connection {
+		case 1:
+			w.Write([]byte(`{"cursor": "c1", "event": "create", "id": "mmm", "object": "message", "attributes": {"id": "mmm", "subject": "Hello"}}` + "\n\n"))
+			w.Write([]byte(`{"cursor": "c2", "event": "delete", "id": "mm2", "object": "message"}` + "\n"))
+		case 2:
+			w.Write([]byte("\n"))
+			w.(http.Flusher).Flush()
+			w.Write([]byte(`{"cursor": "c3", "event": "modify", "id": "ttt", "object": "thread", "attributes": {"id": "ttt", "unread": true}}`))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		}
+	}))
+	defer server.Close()
+
+	var disconnects int
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := New(server.URL)
+	stream := client.StreamDelta(ctx, "zzz", "c0", StreamOptions{
+		OnDisconnect: func(err error) {
+			disconnects++
+		},
+	})
+
+	var deltas []Delta
+	for delta := range stream.Deltas() {
+		deltas = append(deltas, delta)
+		if len(deltas) == 3 {
+			cancel()
+		}
+	}
+
+	if len(deltas) != 3 || deltas[0].Attributes.Message == nil || !deltas[1].IsDelete() || deltas[2].Attributes.Thread == nil {
+		t.Fatalf("Unexpected deltas: %v", deltas)
+	}
+
+	if stream.Cursor() != "c3" {
+		t.Errorf("Unexpected stream cursor: %s", stream.Cursor())
+	}
+
+	if !errors.Is(stream.Err(), context.Canceled) {
+		t.Errorf("Expected the stream to stop on cancellation, got %v", stream.Err())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(cursors) != 2 || cursors[0] != "c0" || cursors[1] != "c2" || disconnects != 1 {
+		t.Errorf("Expected one reconnection from c2, got cursors %v and %d disconnects", cursors, disconnects)
+	}
+}
+
+func TestStreamDeltaPermanentError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type": "invalid_request_error", "message": "Invalid cursor parameter"}`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := New(server.URL)
+	stream := client.StreamDelta(ctx, "zzz", "bad", StreamOptions{})
+
+	for range stream.Deltas() {
+		t.Error("Should not have gotten any delta")
+	}
+
+	var apiErr *APIError
+	if !errors.As(stream.Err(), &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected the stream to stop on the API error, got %v", stream.Err())
+	}
+}
+
+func TestStreamDeltaEmptyResponseBacksOff(t *testing.T) {
+	var mu sync.Mutex
+	var connections int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		connections++
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+
+	client := New(server.URL)
+	stream := client.StreamDelta(ctx, "zzz", "c0", StreamOptions{
+		ReconnectDelay:    50 * time.Millisecond,
+		MaxReconnectDelay: 50 * time.Millisecond,
+	})
+
+	for range stream.Deltas() {
+		t.Error("Should not have gotten any delta")
+	}
+
+	if !errors.Is(stream.Err(), context.DeadlineExceeded) {
+		t.Errorf("Expected the stream to stop on the deadline, got %v", stream.Err())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if connections < 2 || connections > 10 {
+		t.Errorf("Expected empty responses to reconnect with a delay, got %d connections", connections)
+	}
+}
+
+func TestStreamDeltaFromStore(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") != "c0" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte(`{"cursor": "c1", "event": "delete", "id": "mmm", "object": "message"}`))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := NewMemoryCursorStore()
+	store.Save("zzz", "c0")
+
+	client := New(server.URL)
+	stream, err := client.StreamDeltaFromStore(ctx, "zzz", store, StreamOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delta := <-stream.Deltas()
+	if cursor, _ := store.Load("zzz"); cursor != "c0" {
+		t.Errorf("The cursor should not be saved before the delta is acknowledged, got %s", cursor)
+	}
+
+	if err = stream.Ack(delta); err != nil {
+		t.Fatal(err)
+	}
+
+	if cursor, _ := store.Load("zzz"); cursor != "c1" {
+		t.Errorf("Expected the acknowledged cursor c1 to be saved, got %s", cursor)
+	}
+}
@@ -0,0 +1,77 @@
+package gosyncengine
+
+// Draft contains all the details of a draft message. The draft specific
+// Version and ReplyToMessageID fields are part of the embedded Message.
+type Draft struct {
+	Message
+}
+
+// Drafts is a list of draft objects
+type Drafts []Draft
+
+// OutboundMessage describes a message to be saved as a draft or sent
+type OutboundMessage struct {
+	Subject          string        `json:"subject,omitempty"`
+	To               []Participant `json:"to,omitempty"`
+	CC               []Participant `json:"cc,omitempty"`
+	BCC              []Participant `json:"bcc,omitempty"`
+	ReplyTo          []Participant `json:"reply_to,omitempty"`
+	Body             string        `json:"body,omitempty"`
+	ReplyToMessageID string        `json:"reply_to_message_id,omitempty"`
+	FileIDs          []string      `json:"file_ids,omitempty"`
+}
+
+// draftRequest is the body of draft create and delete calls
+type draftRequest struct {
+	OutboundMessage
+	Version *int `json:"version,omitempty"`
+}
+
+// draftUpdateRequest is the body of draft update calls. Unlike draftRequest it sends every content field,
+// so that the draft is fully replaced and fields left empty are cleared.
+type draftUpdateRequest struct {
+	Subject          string        `json:"subject"`
+	To               []Participant `json:"to"`
+	CC               []Participant `json:"cc"`
+	BCC              []Participant `json:"bcc"`
+	ReplyTo          []Participant `json:"reply_to"`
+	Body             string        `json:"body"`
+	ReplyToMessageID string        `json:"reply_to_message_id,omitempty"`
+	FileIDs          []string      `json:"file_ids"`
+	Version          int           `json:"version"`
+}
+
+func newDraftUpdateRequest(message OutboundMessage, version int) draftUpdateRequest {
+	var request = draftUpdateRequest{
+		Subject:          message.Subject,
+		To:               emptyParticipants(message.To),
+		CC:               emptyParticipants(message.CC),
+		BCC:              emptyParticipants(message.BCC),
+		ReplyTo:          emptyParticipants(message.ReplyTo),
+		Body:             message.Body,
+		ReplyToMessageID: message.ReplyToMessageID,
+		FileIDs:          message.FileIDs,
+		Version:          version,
+	}
+
+	if request.FileIDs == nil {
+		request.FileIDs = []string{}
+	}
+
+	return request
+}
+
+// emptyParticipants returns an empty list rather than nil, which would be sent as null
+func emptyParticipants(participants []Participant) []Participant {
+	if participants == nil {
+		return []Participant{}
+	}
+
+	return participants
+}
+
+// sendDraftRequest is the body of a call sending an existing draft
+type sendDraftRequest struct {
+	DraftID string `json:"draft_id"`
+	Version int    `json:"version"`
+}
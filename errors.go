@@ -0,0 +1,144 @@
+package gosyncengine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// maxErrorBodySize limits how much of a failed response body is kept on an APIError
+const maxErrorBodySize = 512
+
+// APIError is returned when the sync engine responds with a non successful status code
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Type       string
+	Message    string
+	// ServerError holds the provider's error detail when sending a message fails
+	ServerError string
+	Body        string
+}
+
+// apiErrorBody is the JSON error payload returned by the sync engine
+type apiErrorBody struct {
+	Type        string `json:"type"`
+	Message     string `json:"message"`
+	ServerError string `json:"server_error"`
+}
+
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+	}
+
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Path = resp.Request.URL.Path
+	}
+
+	var errorBody apiErrorBody
+	if err := json.Unmarshal(body, &errorBody); err == nil {
+		apiErr.Type = errorBody.Type
+		apiErr.Message = errorBody.Message
+		apiErr.ServerError = errorBody.ServerError
+	}
+
+	if len(body) > maxErrorBodySize {
+		body = body[:maxErrorBodySize]
+	}
+	apiErr.Body = string(body)
+
+	return apiErr
+}
+
+func (e *APIError) Error() string {
+	reason := e.Message
+	if reason == "" {
+		reason = e.Body
+	}
+
+	if e.ServerError != "" {
+		reason = fmt.Sprintf("%s ServerError=%s", reason, e.ServerError)
+	}
+
+	if e.Type != "" {
+		return fmt.Sprintf("Request %s %s failed. Status=%d Type=%s Reason=%s", e.Method, e.Path, e.StatusCode, e.Type, reason)
+	}
+
+	return fmt.Sprintf("Request %s %s failed. Status=%d Reason=%s", e.Method, e.Path, e.StatusCode, reason)
+}
+
+// VersionConflictError is returned when a draft was modified since the version given to an update or delete call
+type VersionConflictError struct {
+	DraftID string
+	Version int
+	Err     *APIError
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("Draft %s is no longer at version %d. Reason=%s", e.DraftID, e.Version, e.Err.Message)
+}
+
+// Unwrap returns the underlying APIError
+func (e *VersionConflictError) Unwrap() error {
+	return e.Err
+}
+
+// asVersionConflict converts a 409 APIError returned by a draft call into a VersionConflictError
+func asVersionConflict(err error, draftID string, version int) error {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict {
+		return &VersionConflictError{
+			DraftID: draftID,
+			Version: version,
+			Err:     apiErr,
+		}
+	}
+
+	return err
+}
+
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+func hasStatus(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
+
+// IsNotFound returns true if err is an APIError with a 404 status code
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized returns true if err is an APIError with a 401 status code
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden returns true if err is an APIError with a 403 status code
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsRetryable returns true if err is an APIError caused by a transient sync engine failure
+// (rate limiting or an unavailable server) and the call may succeed if issued again
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && isRetryableStatus(apiErr.StatusCode)
+}
+
+// IsVersionConflict returns true if err is a VersionConflictError
+func IsVersionConflict(err error) bool {
+	var conflictErr *VersionConflictError
+	return errors.As(err, &conflictErr)
+}
@@ -0,0 +1,39 @@
+package gosyncengine
+
+// Event contains the details of a calendar event, such as one attached to a message as an invitation
+type Event struct {
+	ID           string             `json:"id"`
+	AccountID    string             `json:"account_id"`
+	Object       string             `json:"object"`
+	CalendarID   string             `json:"calendar_id"`
+	MessageID    string             `json:"message_id"`
+	Title        string             `json:"title"`
+	Description  string             `json:"description"`
+	Location     string             `json:"location"`
+	Owner        string             `json:"owner"`
+	Participants []EventParticipant `json:"participants"`
+	ReadOnly     bool               `json:"read_only"`
+	Busy         bool               `json:"busy"`
+	Status       string             `json:"status"`
+	When         EventWhen          `json:"when"`
+}
+
+// EventParticipant contains information on a single participant of an event
+type EventParticipant struct {
+	Name    string `json:"name"`
+	Email   string `json:"email"`
+	Status  string `json:"status"`
+	Comment string `json:"comment"`
+}
+
+// EventWhen describes when an event takes place. Depending on Object it is
+// a time, a timespan, a date or a datespan.
+type EventWhen struct {
+	Object    string    `json:"object"`
+	Time      Timestamp `json:"time"`
+	StartTime Timestamp `json:"start_time"`
+	EndTime   Timestamp `json:"end_time"`
+	Date      string    `json:"date"`
+	StartDate string    `json:"start_date"`
+	EndDate   string    `json:"end_date"`
+}
@@ -0,0 +1,16 @@
+package gosyncengine
+
+// File contains the metadata of a single file (such as a message attachment)
+type File struct {
+	ID          string   `json:"id"`
+	AccountID   string   `json:"account_id"`
+	Object      string   `json:"object"`
+	ContentType string   `json:"content_type"`
+	ContentID   string   `json:"content_id"`
+	Filename    string   `json:"filename"`
+	Size        int      `json:"size"`
+	MessageIDs  []string `json:"message_ids"`
+}
+
+// Files is a collection of File objects
+type Files []File
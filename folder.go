@@ -3,6 +3,11 @@ package gosyncengine
 // Folder contains all the details on a specific folder
 type Folder struct {
 	ID          string `json:"id"`
+	AccountID   string `json:"account_id"`
+	Object      string `json:"object"`
 	Name        string `json:"name"`
 	DisplayName string `json:"display_name"`
 }
+
+// Folders is a collection of Folder objects
+type Folders []Folder
@@ -2,12 +2,16 @@ package gosyncengine
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"reflect"
+	"strconv"
+	"time"
 )
 
 var (
@@ -19,62 +23,208 @@ var (
 // SyncEngineAPI provides access to the sync engine API
 type SyncEngineAPI struct {
 	BaseURL string
+
+	client      *http.Client
+	timeout     time.Duration
+	transport   http.RoundTripper
+	userAgent   string
+	retryPolicy RetryPolicy
 }
 
 // New creates a new SyncEngine API object
-func New(baseURL string) *SyncEngineAPI {
-	return &SyncEngineAPI{
-		BaseURL: baseURL,
+func New(baseURL string, options ...Option) *SyncEngineAPI {
+	api := &SyncEngineAPI{
+		BaseURL:   baseURL,
+		userAgent: DefaultUserAgent,
+	}
+
+	for _, option := range options {
+		option(api)
+	}
+
+	api.buildClient()
+
+	return api
+}
+
+func (api *SyncEngineAPI) getClient() *http.Client {
+	if api.client == nil {
+		return http.DefaultClient
 	}
+
+	return api.client
+}
+
+// getLongLivedClient returns the shared client without its overall timeout, for calls
+// which are expected to stay open for long periods
+func (api *SyncEngineAPI) getLongLivedClient() *http.Client {
+	client := api.getClient()
+	if client.Timeout == 0 {
+		return client
+	}
+
+	longLived := *client
+	longLived.Timeout = 0
+	return &longLived
 }
 
 func (api *SyncEngineAPI) getURL(path string) string {
 	return fmt.Sprintf("%s%s", api.BaseURL, path)
 }
 
-func (api *SyncEngineAPI) executeRequest(method string, userID string, path string, requestBody []byte) (*http.Response, error) {
-	var requestBuffer io.Reader
+// apiRequest describes a single call to the sync engine
+type apiRequest struct {
+	method string
+	userID string
+	path   string
+	// body is a JSON request body which can be replayed on retries
+	body []byte
+	// bodyReader is a streamed request body. Requests with a streamed body are never retried.
+	bodyReader  io.Reader
+	contentType string
+	accept      string
+	// longLived requests (long polling, streaming and transfers of files and raw messages) are bound by their context
+	// rather than the client timeout
+	longLived bool
+}
+
+func (api *SyncEngineAPI) executeRequest(ctx context.Context, method string, userID string, path string, requestBody []byte) (*http.Response, error) {
+	var request = apiRequest{
+		method: method,
+		userID: userID,
+		path:   path,
+		body:   requestBody,
+	}
+
 	if requestBody != nil {
-		requestBuffer = bytes.NewBuffer(requestBody)
+		request.contentType = "application/json"
 	}
 
-	var url = api.getURL(path)
-	req, _ := http.NewRequest(method, url, requestBuffer)
-	req.SetBasicAuth(userID, "")
+	return api.do(ctx, request)
+}
 
-	client := &http.Client{}
+func (api *SyncEngineAPI) do(ctx context.Context, request apiRequest) (*http.Response, error) {
+	var url = api.getURL(request.path)
+	var attempts = api.retryPolicy.attempts(request.method)
 
-	var resp *http.Response
-	var err error
+	var client = api.getClient()
+	if request.longLived {
+		client = api.getLongLivedClient()
+	}
+	if request.bodyReader != nil {
+		attempts = 1
+	}
 
-	if resp, err = client.Do(req); err != nil {
-		return nil, err
+	for attempt := 1; ; attempt++ {
+		var requestBuffer io.Reader
+		if request.bodyReader != nil {
+			requestBuffer = request.bodyReader
+		} else if request.body != nil {
+			requestBuffer = bytes.NewBuffer(request.body)
+		}
+
+		var req *http.Request
+		var resp *http.Response
+		var err error
+
+		if req, err = http.NewRequestWithContext(ctx, request.method, url, requestBuffer); err != nil {
+			return nil, err
+		}
+
+		// Server level calls (such as /accounts) are not scoped to an account
+		if request.userID != "" {
+			req.SetBasicAuth(request.userID, "")
+		}
+
+		if api.userAgent != "" {
+			req.Header.Set("User-Agent", api.userAgent)
+		}
+
+		if request.contentType != "" {
+			req.Header.Set("Content-Type", request.contentType)
+		}
+
+		if request.accept != "" {
+			req.Header.Set("Accept", request.accept)
+		}
+
+		resp, err = client.Do(req)
+
+		var result = RetryAttempt{
+			Attempt:  attempt,
+			Method:   request.method,
+			Path:     request.path,
+			Err:      err,
+			Retrying: attempt < attempts && api.retryPolicy.shouldRetry(ctx, resp, err),
+		}
+		if resp != nil {
+			result.StatusCode = resp.StatusCode
+		}
+		if result.Retrying {
+			result.Delay = api.retryPolicy.backoff(attempt, resp)
+		}
+		api.retryPolicy.observe(result)
+
+		if !result.Retrying {
+			return resp, err
+		}
+
+		if resp != nil {
+			discardResponse(resp)
+		}
+
+		if err = sleepContext(ctx, result.Delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func isSuccessStatus(statusCode int) bool {
+	return statusCode >= http.StatusOK && statusCode < http.StatusMultipleChoices
+}
+
+// decodeResponse reads and closes the response body, returning an APIError
+// for non successful responses and unmarshaling the body into result otherwise
+func decodeResponse(resp *http.Response, result interface{}) error {
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("Reading response body failed. Reason: %s", err)
+	}
+
+	if !isSuccessStatus(resp.StatusCode) {
+		return newAPIError(resp, body)
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err = json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("Response deserialization failed. Reason: %s", err)
 	}
 
-	return resp, nil
+	return nil
 }
 
 // GetAccounts returns all the accounts defined on the server
 func (api *SyncEngineAPI) GetAccounts() (Accounts, error) {
-	var req *http.Request
+	return api.GetAccountsContext(context.Background())
+}
+
+// GetAccountsContext returns all the accounts defined on the server using the given context
+func (api *SyncEngineAPI) GetAccountsContext(ctx context.Context) (Accounts, error) {
 	var resp *http.Response
 	var err error
-	req, _ = http.NewRequest(http.MethodGet, api.getURL("/accounts"), nil)
 
-	client := &http.Client{}
-	if resp, err = client.Do(req); err != nil {
+	if resp, err = api.executeRequest(ctx, http.MethodGet, "", "/accounts", nil); err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	body, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Reading request body failed. Status=%d  Reason=%s", resp.StatusCode, string(body))
-	}
-
 	var result Accounts
-	if err = json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("Response deserialization failed. Reason: %s", err)
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -82,45 +232,63 @@ func (api *SyncEngineAPI) GetAccounts() (Accounts, error) {
 
 // GetThreads returns all the threads of the specified account ID
 func (api *SyncEngineAPI) GetThreads(accountID string) (Threads, error) {
+	return api.GetThreadsContext(context.Background(), accountID)
+}
+
+// GetThreadsContext returns all the threads of the specified account ID using the given context
+func (api *SyncEngineAPI) GetThreadsContext(ctx context.Context, accountID string) (Threads, error) {
+	return api.GetThreadsWithQueryContext(ctx, accountID, nil)
+}
+
+// GetThreadsWithQuery returns the threads of the specified account ID matching the given query
+func (api *SyncEngineAPI) GetThreadsWithQuery(accountID string, query *ThreadQuery) (Threads, error) {
+	return api.GetThreadsWithQueryContext(context.Background(), accountID, query)
+}
+
+// GetThreadsWithQueryContext returns the threads of the specified account ID matching the given query using the given context
+func (api *SyncEngineAPI) GetThreadsWithQueryContext(ctx context.Context, accountID string, query *ThreadQuery) (Threads, error) {
 	var resp *http.Response
 	var err error
 
-	if resp, err = api.executeRequest(http.MethodGet, accountID, "/threads", nil); err != nil {
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, withQuery("/threads", query.Encode()), nil); err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	body, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Reading request body failed. Status=%d  Reason=%s", resp.StatusCode, string(body))
-	}
-
 	var result Threads
-	if err = json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("Response deserialization failed. Reason: %s", err)
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	return result, nil
 }
 
+// GetThreadsPage returns a single page of the threads of the specified account ID
+func (api *SyncEngineAPI) GetThreadsPage(accountID string, offset int, limit int) (Threads, error) {
+	return api.GetThreadsPageContext(context.Background(), accountID, offset, limit)
+}
+
+// GetThreadsPageContext returns a single page of the threads of the specified account ID using the given context
+func (api *SyncEngineAPI) GetThreadsPageContext(ctx context.Context, accountID string, offset int, limit int) (Threads, error) {
+	return api.GetThreadsWithQueryContext(ctx, accountID, NewThreadQuery().Offset(offset).Limit(limit))
+}
+
 // GetThreadByID returns a thread by its ID
 func (api *SyncEngineAPI) GetThreadByID(accountID string, threadID string) (*Thread, error) {
+	return api.GetThreadByIDContext(context.Background(), accountID, threadID)
+}
+
+// GetThreadByIDContext returns a thread by its ID using the given context
+func (api *SyncEngineAPI) GetThreadByIDContext(ctx context.Context, accountID string, threadID string) (*Thread, error) {
 	var resp *http.Response
 	var err error
 
-	if resp, err = api.executeRequest(http.MethodGet, accountID, fmt.Sprintf("/threads/%s", threadID), nil); err != nil {
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, fmt.Sprintf("/threads/%s", threadID), nil); err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	body, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Reading request body failed. Status=%d  Reason=%s", resp.StatusCode, string(body))
-	}
-
 	var result = &Thread{}
-	if err = json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("Response deserialization failed. Reason: %s", err)
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
 	}
 
 	return result, nil
@@ -128,91 +296,1111 @@ func (api *SyncEngineAPI) GetThreadByID(accountID string, threadID string) (*Thr
 
 // GetMessageByID returns a single message by its ID
 func (api *SyncEngineAPI) GetMessageByID(accountID string, messageID string) (*Message, error) {
+	return api.GetMessageByIDContext(context.Background(), accountID, messageID)
+}
+
+// GetMessageByIDContext returns a single message by its ID using the given context
+func (api *SyncEngineAPI) GetMessageByIDContext(ctx context.Context, accountID string, messageID string) (*Message, error) {
 	var resp *http.Response
 	var err error
 
-	if resp, err = api.executeRequest(http.MethodGet, accountID, fmt.Sprintf("/messages/%s", messageID), nil); err != nil {
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, fmt.Sprintf("/messages/%s", messageID), nil); err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	body, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Reading request body failed. Status=%d  Reason=%s", resp.StatusCode, string(body))
+	var result = &Message{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetExpandedMessageByID returns a single message by its ID, including the headers only returned by the expanded view
+func (api *SyncEngineAPI) GetExpandedMessageByID(accountID string, messageID string) (*Message, error) {
+	return api.GetExpandedMessageByIDContext(context.Background(), accountID, messageID)
+}
+
+// GetExpandedMessageByIDContext returns a single message by its ID, including the headers only returned by
+// the expanded view, using the given context
+func (api *SyncEngineAPI) GetExpandedMessageByIDContext(ctx context.Context, accountID string, messageID string) (*Message, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, fmt.Sprintf("/messages/%s?view=expanded", messageID), nil); err != nil {
+		return nil, err
 	}
 
 	var result = &Message{}
-	if err = json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("Response deserialization failed. Reason: %s", err)
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
 	}
 
 	return result, nil
 }
 
-// GetThreadMessages returns all of the messages associated with the specified thread ID
-func (api *SyncEngineAPI) GetThreadMessages(accountID string, threadID string) (Messages, error) {
+// GetRawMessage returns the original RFC 822 source of a message. The caller must close the returned reader.
+func (api *SyncEngineAPI) GetRawMessage(accountID string, messageID string) (io.ReadCloser, error) {
+	return api.GetRawMessageContext(context.Background(), accountID, messageID)
+}
+
+// GetRawMessageContext returns the original RFC 822 source of a message using the given context.
+// Reading the source is bound by the context rather than by the client timeout. The caller must close the returned reader.
+func (api *SyncEngineAPI) GetRawMessageContext(ctx context.Context, accountID string, messageID string) (io.ReadCloser, error) {
+	var resp *http.Response
+	var err error
+
+	var request = apiRequest{
+		method:    http.MethodGet,
+		userID:    accountID,
+		path:      fmt.Sprintf("/messages/%s", messageID),
+		accept:    "message/rfc822",
+		longLived: true,
+	}
+
+	if resp, err = api.do(ctx, request); err != nil {
+		return nil, err
+	}
+
+	if !isSuccessStatus(resp.StatusCode) {
+		return nil, decodeResponse(resp, nil)
+	}
+
+	return resp.Body, nil
+}
+
+// GetParsedRawMessage fetches the original RFC 822 source of a message and parses it into headers and body parts
+func (api *SyncEngineAPI) GetParsedRawMessage(accountID string, messageID string) (*RawMessage, error) {
+	return api.GetParsedRawMessageContext(context.Background(), accountID, messageID)
+}
+
+// GetParsedRawMessageContext fetches the original RFC 822 source of a message and parses it into headers
+// and body parts using the given context
+func (api *SyncEngineAPI) GetParsedRawMessageContext(ctx context.Context, accountID string, messageID string) (*RawMessage, error) {
+	var body io.ReadCloser
+	var err error
+
+	if body, err = api.GetRawMessageContext(ctx, accountID, messageID); err != nil {
+		return nil, err
+	}
+
+	defer body.Close()
+	return ParseRawMessage(body)
+}
+
+// UpdateThread applies the given changes to a thread and returns the updated thread
+func (api *SyncEngineAPI) UpdateThread(accountID string, threadID string, update ThreadUpdate) (*Thread, error) {
+	return api.UpdateThreadContext(context.Background(), accountID, threadID, update)
+}
+
+// UpdateThreadContext applies the given changes to a thread and returns the updated thread using the given context
+func (api *SyncEngineAPI) UpdateThreadContext(ctx context.Context, accountID string, threadID string, update ThreadUpdate) (*Thread, error) {
+	var requestBody []byte
 	var resp *http.Response
 	var err error
 
-	if resp, err = api.executeRequest(http.MethodGet, accountID, fmt.Sprintf("/messages/?thread_id=%s", threadID), nil); err != nil {
+	if requestBody, err = json.Marshal(update); err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	body, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Reading request body failed. Status=%d  Reason=%s", resp.StatusCode, string(body))
+	if resp, err = api.executeRequest(ctx, http.MethodPut, accountID, fmt.Sprintf("/threads/%s", threadID), requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Thread{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MarkThreadRead marks all the messages of a thread as read
+func (api *SyncEngineAPI) MarkThreadRead(accountID string, threadID string) (*Thread, error) {
+	return api.MarkThreadReadContext(context.Background(), accountID, threadID)
+}
+
+// MarkThreadReadContext marks all the messages of a thread as read using the given context
+func (api *SyncEngineAPI) MarkThreadReadContext(ctx context.Context, accountID string, threadID string) (*Thread, error) {
+	return api.UpdateThreadContext(ctx, accountID, threadID, ThreadUpdate{Unread: Bool(false)})
+}
+
+// MarkThreadUnread marks all the messages of a thread as unread
+func (api *SyncEngineAPI) MarkThreadUnread(accountID string, threadID string) (*Thread, error) {
+	return api.MarkThreadUnreadContext(context.Background(), accountID, threadID)
+}
+
+// MarkThreadUnreadContext marks all the messages of a thread as unread using the given context
+func (api *SyncEngineAPI) MarkThreadUnreadContext(ctx context.Context, accountID string, threadID string) (*Thread, error) {
+	return api.UpdateThreadContext(ctx, accountID, threadID, ThreadUpdate{Unread: Bool(true)})
+}
+
+// StarThread stars a thread
+func (api *SyncEngineAPI) StarThread(accountID string, threadID string) (*Thread, error) {
+	return api.StarThreadContext(context.Background(), accountID, threadID)
+}
+
+// StarThreadContext stars a thread using the given context
+func (api *SyncEngineAPI) StarThreadContext(ctx context.Context, accountID string, threadID string) (*Thread, error) {
+	return api.UpdateThreadContext(ctx, accountID, threadID, ThreadUpdate{Starred: Bool(true)})
+}
+
+// UnstarThread removes the star from a thread
+func (api *SyncEngineAPI) UnstarThread(accountID string, threadID string) (*Thread, error) {
+	return api.UnstarThreadContext(context.Background(), accountID, threadID)
+}
+
+// UnstarThreadContext removes the star from a thread using the given context
+func (api *SyncEngineAPI) UnstarThreadContext(ctx context.Context, accountID string, threadID string) (*Thread, error) {
+	return api.UpdateThreadContext(ctx, accountID, threadID, ThreadUpdate{Starred: Bool(false)})
+}
+
+// MoveThreadToFolder moves all the messages of a thread to the given folder
+func (api *SyncEngineAPI) MoveThreadToFolder(accountID string, threadID string, folderID string) (*Thread, error) {
+	return api.MoveThreadToFolderContext(context.Background(), accountID, threadID, folderID)
+}
+
+// MoveThreadToFolderContext moves all the messages of a thread to the given folder using the given context
+func (api *SyncEngineAPI) MoveThreadToFolderContext(ctx context.Context, accountID string, threadID string, folderID string) (*Thread, error) {
+	return api.UpdateThreadContext(ctx, accountID, threadID, ThreadUpdate{FolderID: String(folderID)})
+}
+
+// SetThreadLabels replaces the labels of a thread with the given label IDs. An empty list removes all the labels.
+func (api *SyncEngineAPI) SetThreadLabels(accountID string, threadID string, labelIDs []string) (*Thread, error) {
+	return api.SetThreadLabelsContext(context.Background(), accountID, threadID, labelIDs)
+}
+
+// SetThreadLabelsContext replaces the labels of a thread with the given label IDs using the given context
+func (api *SyncEngineAPI) SetThreadLabelsContext(ctx context.Context, accountID string, threadID string, labelIDs []string) (*Thread, error) {
+	if labelIDs == nil {
+		labelIDs = []string{}
+	}
+
+	return api.UpdateThreadContext(ctx, accountID, threadID, ThreadUpdate{LabelIDs: labelIDs})
+}
+
+// UpdateMessage applies the given changes to a message and returns the updated message
+func (api *SyncEngineAPI) UpdateMessage(accountID string, messageID string, update MessageUpdate) (*Message, error) {
+	return api.UpdateMessageContext(context.Background(), accountID, messageID, update)
+}
+
+// UpdateMessageContext applies the given changes to a message and returns the updated message using the given context
+func (api *SyncEngineAPI) UpdateMessageContext(ctx context.Context, accountID string, messageID string, update MessageUpdate) (*Message, error) {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(update); err != nil {
+		return nil, err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodPut, accountID, fmt.Sprintf("/messages/%s", messageID), requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Message{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// MarkMessageRead marks a message as read
+func (api *SyncEngineAPI) MarkMessageRead(accountID string, messageID string) (*Message, error) {
+	return api.MarkMessageReadContext(context.Background(), accountID, messageID)
+}
+
+// MarkMessageReadContext marks a message as read using the given context
+func (api *SyncEngineAPI) MarkMessageReadContext(ctx context.Context, accountID string, messageID string) (*Message, error) {
+	return api.UpdateMessageContext(ctx, accountID, messageID, MessageUpdate{Unread: Bool(false)})
+}
+
+// MarkMessageUnread marks a message as unread
+func (api *SyncEngineAPI) MarkMessageUnread(accountID string, messageID string) (*Message, error) {
+	return api.MarkMessageUnreadContext(context.Background(), accountID, messageID)
+}
+
+// MarkMessageUnreadContext marks a message as unread using the given context
+func (api *SyncEngineAPI) MarkMessageUnreadContext(ctx context.Context, accountID string, messageID string) (*Message, error) {
+	return api.UpdateMessageContext(ctx, accountID, messageID, MessageUpdate{Unread: Bool(true)})
+}
+
+// StarMessage stars a message
+func (api *SyncEngineAPI) StarMessage(accountID string, messageID string) (*Message, error) {
+	return api.StarMessageContext(context.Background(), accountID, messageID)
+}
+
+// StarMessageContext stars a message using the given context
+func (api *SyncEngineAPI) StarMessageContext(ctx context.Context, accountID string, messageID string) (*Message, error) {
+	return api.UpdateMessageContext(ctx, accountID, messageID, MessageUpdate{Starred: Bool(true)})
+}
+
+// UnstarMessage removes the star from a message
+func (api *SyncEngineAPI) UnstarMessage(accountID string, messageID string) (*Message, error) {
+	return api.UnstarMessageContext(context.Background(), accountID, messageID)
+}
+
+// UnstarMessageContext removes the star from a message using the given context
+func (api *SyncEngineAPI) UnstarMessageContext(ctx context.Context, accountID string, messageID string) (*Message, error) {
+	return api.UpdateMessageContext(ctx, accountID, messageID, MessageUpdate{Starred: Bool(false)})
+}
+
+// MoveMessage moves a message to the given folder
+func (api *SyncEngineAPI) MoveMessage(accountID string, messageID string, folderID string) (*Message, error) {
+	return api.MoveMessageContext(context.Background(), accountID, messageID, folderID)
+}
+
+// MoveMessageContext moves a message to the given folder using the given context
+func (api *SyncEngineAPI) MoveMessageContext(ctx context.Context, accountID string, messageID string, folderID string) (*Message, error) {
+	return api.UpdateMessageContext(ctx, accountID, messageID, MessageUpdate{FolderID: String(folderID)})
+}
+
+// SetMessageLabels replaces the labels of a message with the given label IDs. An empty list removes all the labels.
+func (api *SyncEngineAPI) SetMessageLabels(accountID string, messageID string, labelIDs []string) (*Message, error) {
+	return api.SetMessageLabelsContext(context.Background(), accountID, messageID, labelIDs)
+}
+
+// SetMessageLabelsContext replaces the labels of a message with the given label IDs using the given context
+func (api *SyncEngineAPI) SetMessageLabelsContext(ctx context.Context, accountID string, messageID string, labelIDs []string) (*Message, error) {
+	if labelIDs == nil {
+		labelIDs = []string{}
+	}
+
+	return api.UpdateMessageContext(ctx, accountID, messageID, MessageUpdate{LabelIDs: labelIDs})
+}
+
+// GetMessages returns the messages of the specified account ID matching the given query
+func (api *SyncEngineAPI) GetMessages(accountID string, query *MessageQuery) (Messages, error) {
+	return api.GetMessagesContext(context.Background(), accountID, query)
+}
+
+// GetMessagesContext returns the messages of the specified account ID matching the given query using the given context
+func (api *SyncEngineAPI) GetMessagesContext(ctx context.Context, accountID string, query *MessageQuery) (Messages, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, withQuery("/messages/", query.Encode()), nil); err != nil {
+		return nil, err
 	}
 
 	var result = Messages{}
-	if err = json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("Response deserialization failed. Reason: %s", err)
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
 	return result, nil
 }
 
-// GetDeltaLatestCursor returns the latest cursor available
-func (api *SyncEngineAPI) GetDeltaLatestCursor(accountID string) (*DeltaCursor, error) {
+// GetThreadMessages returns all of the messages associated with the specified thread ID
+func (api *SyncEngineAPI) GetThreadMessages(accountID string, threadID string) (Messages, error) {
+	return api.GetThreadMessagesContext(context.Background(), accountID, threadID)
+}
+
+// GetThreadMessagesContext returns all of the messages associated with the specified thread ID using the given context
+func (api *SyncEngineAPI) GetThreadMessagesContext(ctx context.Context, accountID string, threadID string) (Messages, error) {
+	return api.GetThreadMessagesWithQueryContext(ctx, accountID, threadID, nil)
+}
+
+// GetThreadMessagesWithQuery returns the messages associated with the specified thread ID matching the given query
+func (api *SyncEngineAPI) GetThreadMessagesWithQuery(accountID string, threadID string, query *MessageQuery) (Messages, error) {
+	return api.GetThreadMessagesWithQueryContext(context.Background(), accountID, threadID, query)
+}
+
+// GetThreadMessagesWithQueryContext returns the messages associated with the specified thread ID matching the given query using the given context
+func (api *SyncEngineAPI) GetThreadMessagesWithQueryContext(ctx context.Context, accountID string, threadID string, query *MessageQuery) (Messages, error) {
+	return api.GetMessagesContext(ctx, accountID, query.clone().ThreadID(threadID))
+}
+
+// GetThreadMessagesPage returns a single page of the messages associated with the specified thread ID
+func (api *SyncEngineAPI) GetThreadMessagesPage(accountID string, threadID string, offset int, limit int) (Messages, error) {
+	return api.GetThreadMessagesPageContext(context.Background(), accountID, threadID, offset, limit)
+}
+
+// GetThreadMessagesPageContext returns a single page of the messages associated with the specified thread ID using the given context
+func (api *SyncEngineAPI) GetThreadMessagesPageContext(ctx context.Context, accountID string, threadID string, offset int, limit int) (Messages, error) {
+	return api.GetThreadMessagesWithQueryContext(ctx, accountID, threadID, NewMessageQuery().Offset(offset).Limit(limit))
+}
+
+// GetDrafts returns all the drafts of the specified account ID
+func (api *SyncEngineAPI) GetDrafts(accountID string) (Drafts, error) {
+	return api.GetDraftsContext(context.Background(), accountID)
+}
+
+// GetDraftsContext returns all the drafts of the specified account ID using the given context
+func (api *SyncEngineAPI) GetDraftsContext(ctx context.Context, accountID string) (Drafts, error) {
 	var resp *http.Response
 	var err error
 
-	if resp, err = api.executeRequest(http.MethodPost, accountID, "/delta/latest_cursor", nil); err != nil {
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, "/drafts", nil); err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	body, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Reading request body failed. Status=%d  Reason=%s", resp.StatusCode, string(body))
+	var result Drafts
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
 	}
 
-	var result = &DeltaCursor{}
-	if err = json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("Response deserialization failed. Reason: %s", err)
+	return result, nil
+}
+
+// GetDraftByID returns a draft by its ID
+func (api *SyncEngineAPI) GetDraftByID(accountID string, draftID string) (*Draft, error) {
+	return api.GetDraftByIDContext(context.Background(), accountID, draftID)
+}
+
+// GetDraftByIDContext returns a draft by its ID using the given context
+func (api *SyncEngineAPI) GetDraftByIDContext(ctx context.Context, accountID string, draftID string) (*Draft, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, fmt.Sprintf("/drafts/%s", draftID), nil); err != nil {
+		return nil, err
+	}
+
+	var result = &Draft{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
 	}
 
 	return result, nil
 }
 
-// GetDeltaMessages will return only messages from the given cursor
-func (api *SyncEngineAPI) GetDeltaMessages(accountID string, cursor string) (*DeltaMessages, error) {
+// CreateDraft saves a new draft
+func (api *SyncEngineAPI) CreateDraft(accountID string, message OutboundMessage) (*Draft, error) {
+	return api.CreateDraftContext(context.Background(), accountID, message)
+}
+
+// CreateDraftContext saves a new draft using the given context
+func (api *SyncEngineAPI) CreateDraftContext(ctx context.Context, accountID string, message OutboundMessage) (*Draft, error) {
+	var requestBody []byte
 	var resp *http.Response
 	var err error
 
-	if resp, err = api.executeRequest(http.MethodGet, accountID, fmt.Sprintf("/delta?cursor=%s&view=expanded&include_types=message", cursor), nil); err != nil {
+	if requestBody, err = json.Marshal(draftRequest{OutboundMessage: message}); err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	body, _ := ioutil.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Reading request body failed. Status=%d  Reason=%s", resp.StatusCode, string(body))
+	if resp, err = api.executeRequest(ctx, http.MethodPost, accountID, "/drafts", requestBody); err != nil {
+		return nil, err
 	}
 
-	var result = &DeltaMessages{}
-	if err = json.Unmarshal(body, &result); err != nil {
-		return nil, fmt.Errorf("Response deserialization failed. Reason: %s", err)
+	var result = &Draft{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateDraft replaces the content of a draft: fields left empty in message are cleared, except
+// ReplyToMessageID which is kept when empty. The version must be the current version of the draft,
+// otherwise a VersionConflictError is returned.
+func (api *SyncEngineAPI) UpdateDraft(accountID string, draftID string, version int, message OutboundMessage) (*Draft, error) {
+	return api.UpdateDraftContext(context.Background(), accountID, draftID, version, message)
+}
+
+// UpdateDraftContext replaces the content of a draft using the given context. The version must be the current
+// version of the draft, otherwise a VersionConflictError is returned.
+func (api *SyncEngineAPI) UpdateDraftContext(ctx context.Context, accountID string, draftID string, version int, message OutboundMessage) (*Draft, error) {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(newDraftUpdateRequest(message, version)); err != nil {
+		return nil, err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodPut, accountID, fmt.Sprintf("/drafts/%s", draftID), requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Draft{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, asVersionConflict(err, draftID, version)
+	}
+
+	return result, nil
+}
+
+// DeleteDraft deletes a draft. The version must be the current version of the draft,
+// otherwise a VersionConflictError is returned.
+func (api *SyncEngineAPI) DeleteDraft(accountID string, draftID string, version int) error {
+	return api.DeleteDraftContext(context.Background(), accountID, draftID, version)
+}
+
+// DeleteDraftContext deletes a draft using the given context. The version must be the current
+// version of the draft, otherwise a VersionConflictError is returned.
+func (api *SyncEngineAPI) DeleteDraftContext(ctx context.Context, accountID string, draftID string, version int) error {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(draftRequest{Version: &version}); err != nil {
+		return err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodDelete, accountID, fmt.Sprintf("/drafts/%s", draftID), requestBody); err != nil {
+		return err
+	}
+
+	return asVersionConflict(decodeResponse(resp, nil), draftID, version)
+}
+
+// SendMessage sends a new message and returns the sent message.
+// Provider failures are returned as an APIError carrying the provider's ServerError detail.
+func (api *SyncEngineAPI) SendMessage(accountID string, message OutboundMessage) (*Message, error) {
+	return api.SendMessageContext(context.Background(), accountID, message)
+}
+
+// SendMessageContext sends a new message and returns the sent message using the given context.
+// Provider failures are returned as an APIError carrying the provider's ServerError detail.
+func (api *SyncEngineAPI) SendMessageContext(ctx context.Context, accountID string, message OutboundMessage) (*Message, error) {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(message); err != nil {
+		return nil, err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodPost, accountID, "/send", requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Message{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SendDraft sends an existing draft and returns the sent message. The version must be the current
+// version of the draft, otherwise a VersionConflictError is returned.
+func (api *SyncEngineAPI) SendDraft(accountID string, draftID string, version int) (*Message, error) {
+	return api.SendDraftContext(context.Background(), accountID, draftID, version)
+}
+
+// SendDraftContext sends an existing draft and returns the sent message using the given context.
+// The version must be the current version of the draft, otherwise a VersionConflictError is returned.
+func (api *SyncEngineAPI) SendDraftContext(ctx context.Context, accountID string, draftID string, version int) (*Message, error) {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(sendDraftRequest{DraftID: draftID, Version: version}); err != nil {
+		return nil, err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodPost, accountID, "/send", requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Message{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, asVersionConflict(err, draftID, version)
+	}
+
+	return result, nil
+}
+
+// SendRawMIME sends a fully formed RFC 5322 message read from mime and returns the created message.
+// The message is streamed to the sync engine without being buffered in memory.
+func (api *SyncEngineAPI) SendRawMIME(accountID string, mime io.Reader) (*Message, error) {
+	return api.SendRawMIMEContext(context.Background(), accountID, mime)
+}
+
+// SendRawMIMEContext sends a fully formed RFC 5322 message read from mime and returns the created message
+// using the given context. The message is streamed to the sync engine without being buffered in memory,
+// and the transfer is bound by the context rather than by the client timeout.
+func (api *SyncEngineAPI) SendRawMIMEContext(ctx context.Context, accountID string, mime io.Reader) (*Message, error) {
+	var resp *http.Response
+	var err error
+
+	var request = apiRequest{
+		method:      http.MethodPost,
+		userID:      accountID,
+		path:        "/send",
+		bodyReader:  mime,
+		contentType: "message/rfc822",
+		longLived:   true,
+	}
+
+	if resp, err = api.do(ctx, request); err != nil {
+		return nil, err
+	}
+
+	var result = &Message{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetFiles returns the metadata of all the files of the specified account ID
+func (api *SyncEngineAPI) GetFiles(accountID string) (Files, error) {
+	return api.GetFilesContext(context.Background(), accountID)
+}
+
+// GetFilesContext returns the metadata of all the files of the specified account ID using the given context
+func (api *SyncEngineAPI) GetFilesContext(ctx context.Context, accountID string) (Files, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, "/files", nil); err != nil {
+		return nil, err
+	}
+
+	var result Files
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetFileByID returns the metadata of a file by its ID
+func (api *SyncEngineAPI) GetFileByID(accountID string, fileID string) (*File, error) {
+	return api.GetFileByIDContext(context.Background(), accountID, fileID)
+}
+
+// GetFileByIDContext returns the metadata of a file by its ID using the given context
+func (api *SyncEngineAPI) GetFileByIDContext(ctx context.Context, accountID string, fileID string) (*File, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, fmt.Sprintf("/files/%s", fileID), nil); err != nil {
+		return nil, err
+	}
+
+	var result = &File{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DownloadFile streams the content of a file into w and returns the number of bytes written
+func (api *SyncEngineAPI) DownloadFile(accountID string, fileID string, w io.Writer) (int64, error) {
+	return api.DownloadFileContext(context.Background(), accountID, fileID, w)
+}
+
+// DownloadFileContext streams the content of a file into w and returns the number of bytes written using the given context.
+// The transfer is bound by the context rather than by the client timeout.
+func (api *SyncEngineAPI) DownloadFileContext(ctx context.Context, accountID string, fileID string, w io.Writer) (int64, error) {
+	var resp *http.Response
+	var err error
+
+	var request = apiRequest{
+		method:    http.MethodGet,
+		userID:    accountID,
+		path:      fmt.Sprintf("/files/%s/download", fileID),
+		longLived: true,
+	}
+
+	if resp, err = api.do(ctx, request); err != nil {
+		return 0, err
+	}
+
+	if !isSuccessStatus(resp.StatusCode) {
+		return 0, decodeResponse(resp, nil)
+	}
+
+	defer resp.Body.Close()
+	return io.Copy(w, resp.Body)
+}
+
+// UploadFile uploads the content read from r as a new file, which can then be attached to drafts and sent messages.
+// The content is streamed to the sync engine without being buffered in memory.
+func (api *SyncEngineAPI) UploadFile(accountID string, filename string, r io.Reader) (*File, error) {
+	return api.UploadFileContext(context.Background(), accountID, filename, r)
+}
+
+// UploadFileContext uploads the content read from r as a new file using the given context.
+// The content is streamed to the sync engine without being buffered in memory, and the transfer
+// is bound by the context rather than by the client timeout.
+func (api *SyncEngineAPI) UploadFileContext(ctx context.Context, accountID string, filename string, r io.Reader) (*File, error) {
+	var resp *http.Response
+	var err error
+
+	bodyReader, bodyWriter := io.Pipe()
+	defer bodyReader.Close()
+
+	form := multipart.NewWriter(bodyWriter)
+	go func() {
+		part, err := form.CreateFormFile("file", filename)
+		if err == nil {
+			_, err = io.Copy(part, r)
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		bodyWriter.CloseWithError(err)
+	}()
+
+	var request = apiRequest{
+		method:      http.MethodPost,
+		userID:      accountID,
+		path:        "/files",
+		bodyReader:  bodyReader,
+		contentType: form.FormDataContentType(),
+		longLived:   true,
+	}
+
+	if resp, err = api.do(ctx, request); err != nil {
+		return nil, err
+	}
+
+	var result Files
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("Upload of %s returned no file", filename)
+	}
+
+	return &result[0], nil
+}
+
+// GetFolders returns all the folders of the specified account ID
+func (api *SyncEngineAPI) GetFolders(accountID string) (Folders, error) {
+	return api.GetFoldersContext(context.Background(), accountID)
+}
+
+// GetFoldersContext returns all the folders of the specified account ID using the given context
+func (api *SyncEngineAPI) GetFoldersContext(ctx context.Context, accountID string) (Folders, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, "/folders", nil); err != nil {
+		return nil, err
+	}
+
+	var result Folders
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetFolderByID returns a folder by its ID
+func (api *SyncEngineAPI) GetFolderByID(accountID string, folderID string) (*Folder, error) {
+	return api.GetFolderByIDContext(context.Background(), accountID, folderID)
+}
+
+// GetFolderByIDContext returns a folder by its ID using the given context
+func (api *SyncEngineAPI) GetFolderByIDContext(ctx context.Context, accountID string, folderID string) (*Folder, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, fmt.Sprintf("/folders/%s", folderID), nil); err != nil {
+		return nil, err
+	}
+
+	var result = &Folder{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateFolder creates a new folder with the given display name
+func (api *SyncEngineAPI) CreateFolder(accountID string, displayName string) (*Folder, error) {
+	return api.CreateFolderContext(context.Background(), accountID, displayName)
+}
+
+// CreateFolderContext creates a new folder with the given display name using the given context
+func (api *SyncEngineAPI) CreateFolderContext(ctx context.Context, accountID string, displayName string) (*Folder, error) {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(categoryRequest{DisplayName: displayName}); err != nil {
+		return nil, err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodPost, accountID, "/folders", requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Folder{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateFolder renames a folder by setting its display name
+func (api *SyncEngineAPI) UpdateFolder(accountID string, folderID string, displayName string) (*Folder, error) {
+	return api.UpdateFolderContext(context.Background(), accountID, folderID, displayName)
+}
+
+// UpdateFolderContext renames a folder by setting its display name using the given context
+func (api *SyncEngineAPI) UpdateFolderContext(ctx context.Context, accountID string, folderID string, displayName string) (*Folder, error) {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(categoryRequest{DisplayName: displayName}); err != nil {
+		return nil, err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodPut, accountID, fmt.Sprintf("/folders/%s", folderID), requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Folder{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteFolder deletes a folder by its ID
+func (api *SyncEngineAPI) DeleteFolder(accountID string, folderID string) error {
+	return api.DeleteFolderContext(context.Background(), accountID, folderID)
+}
+
+// DeleteFolderContext deletes a folder by its ID using the given context
+func (api *SyncEngineAPI) DeleteFolderContext(ctx context.Context, accountID string, folderID string) error {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodDelete, accountID, fmt.Sprintf("/folders/%s", folderID), nil); err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// GetLabels returns all the labels of the specified account ID
+func (api *SyncEngineAPI) GetLabels(accountID string) (Labels, error) {
+	return api.GetLabelsContext(context.Background(), accountID)
+}
+
+// GetLabelsContext returns all the labels of the specified account ID using the given context
+func (api *SyncEngineAPI) GetLabelsContext(ctx context.Context, accountID string) (Labels, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, "/labels", nil); err != nil {
+		return nil, err
+	}
+
+	var result Labels
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetLabelByID returns a label by its ID
+func (api *SyncEngineAPI) GetLabelByID(accountID string, labelID string) (*Label, error) {
+	return api.GetLabelByIDContext(context.Background(), accountID, labelID)
+}
+
+// GetLabelByIDContext returns a label by its ID using the given context
+func (api *SyncEngineAPI) GetLabelByIDContext(ctx context.Context, accountID string, labelID string) (*Label, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, fmt.Sprintf("/labels/%s", labelID), nil); err != nil {
+		return nil, err
+	}
+
+	var result = &Label{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateLabel creates a new label with the given display name
+func (api *SyncEngineAPI) CreateLabel(accountID string, displayName string) (*Label, error) {
+	return api.CreateLabelContext(context.Background(), accountID, displayName)
+}
+
+// CreateLabelContext creates a new label with the given display name using the given context
+func (api *SyncEngineAPI) CreateLabelContext(ctx context.Context, accountID string, displayName string) (*Label, error) {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(categoryRequest{DisplayName: displayName}); err != nil {
+		return nil, err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodPost, accountID, "/labels", requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Label{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateLabel renames a label by setting its display name
+func (api *SyncEngineAPI) UpdateLabel(accountID string, labelID string, displayName string) (*Label, error) {
+	return api.UpdateLabelContext(context.Background(), accountID, labelID, displayName)
+}
+
+// UpdateLabelContext renames a label by setting its display name using the given context
+func (api *SyncEngineAPI) UpdateLabelContext(ctx context.Context, accountID string, labelID string, displayName string) (*Label, error) {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(categoryRequest{DisplayName: displayName}); err != nil {
+		return nil, err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodPut, accountID, fmt.Sprintf("/labels/%s", labelID), requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Label{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteLabel deletes a label by its ID
+func (api *SyncEngineAPI) DeleteLabel(accountID string, labelID string) error {
+	return api.DeleteLabelContext(context.Background(), accountID, labelID)
+}
+
+// DeleteLabelContext deletes a label by its ID using the given context
+func (api *SyncEngineAPI) DeleteLabelContext(ctx context.Context, accountID string, labelID string) error {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodDelete, accountID, fmt.Sprintf("/labels/%s", labelID), nil); err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// GetCategories returns all the folders or labels of the given account, depending on its organization unit
+func (api *SyncEngineAPI) GetCategories(account Account) (Categories, error) {
+	return api.GetCategoriesContext(context.Background(), account)
+}
+
+// GetCategoriesContext returns all the folders or labels of the given account, depending on its organization unit, using the given context
+func (api *SyncEngineAPI) GetCategoriesContext(ctx context.Context, account Account) (Categories, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, account.apiID(), categoryPath(account), nil); err != nil {
+		return nil, err
+	}
+
+	var result Categories
+	if err = decodeResponse(resp, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateCategory creates a new folder or label, depending on the organization unit of the given account
+func (api *SyncEngineAPI) CreateCategory(account Account, displayName string) (*Category, error) {
+	return api.CreateCategoryContext(context.Background(), account, displayName)
+}
+
+// CreateCategoryContext creates a new folder or label, depending on the organization unit of the given account, using the given context
+func (api *SyncEngineAPI) CreateCategoryContext(ctx context.Context, account Account, displayName string) (*Category, error) {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(categoryRequest{DisplayName: displayName}); err != nil {
+		return nil, err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodPost, account.apiID(), categoryPath(account), requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Category{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateCategory renames a folder or label, depending on the organization unit of the given account
+func (api *SyncEngineAPI) UpdateCategory(account Account, categoryID string, displayName string) (*Category, error) {
+	return api.UpdateCategoryContext(context.Background(), account, categoryID, displayName)
+}
+
+// UpdateCategoryContext renames a folder or label, depending on the organization unit of the given account, using the given context
+func (api *SyncEngineAPI) UpdateCategoryContext(ctx context.Context, account Account, categoryID string, displayName string) (*Category, error) {
+	var requestBody []byte
+	var resp *http.Response
+	var err error
+
+	if requestBody, err = json.Marshal(categoryRequest{DisplayName: displayName}); err != nil {
+		return nil, err
+	}
+
+	if resp, err = api.executeRequest(ctx, http.MethodPut, account.apiID(), fmt.Sprintf("%s/%s", categoryPath(account), categoryID), requestBody); err != nil {
+		return nil, err
+	}
+
+	var result = &Category{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteCategory deletes a folder or label, depending on the organization unit of the given account
+func (api *SyncEngineAPI) DeleteCategory(account Account, categoryID string) error {
+	return api.DeleteCategoryContext(context.Background(), account, categoryID)
+}
+
+// DeleteCategoryContext deletes a folder or label, depending on the organization unit of the given account, using the given context
+func (api *SyncEngineAPI) DeleteCategoryContext(ctx context.Context, account Account, categoryID string) error {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodDelete, account.apiID(), fmt.Sprintf("%s/%s", categoryPath(account), categoryID), nil); err != nil {
+		return err
+	}
+
+	return decodeResponse(resp, nil)
+}
+
+// GetDeltaLatestCursor returns the latest cursor available
+func (api *SyncEngineAPI) GetDeltaLatestCursor(accountID string) (*DeltaCursor, error) {
+	return api.GetDeltaLatestCursorContext(context.Background(), accountID)
+}
+
+// GetDeltaLatestCursorContext returns the latest cursor available using the given context
+func (api *SyncEngineAPI) GetDeltaLatestCursorContext(ctx context.Context, accountID string) (*DeltaCursor, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodPost, accountID, "/delta/latest_cursor", nil); err != nil {
+		return nil, err
+	}
+
+	var result = &DeltaCursor{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetDeltaMessages will return only messages from the given cursor
+func (api *SyncEngineAPI) GetDeltaMessages(accountID string, cursor string) (*DeltaMessages, error) {
+	return api.GetDeltaMessagesContext(context.Background(), accountID, cursor)
+}
+
+// GetDeltaMessagesContext will return only messages from the given cursor using the given context
+func (api *SyncEngineAPI) GetDeltaMessagesContext(ctx context.Context, accountID string, cursor string) (*DeltaMessages, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, withQuery("/delta", deltaMessagesOptions.encode(cursor)), nil); err != nil {
+		return nil, err
+	}
+
+	var result = &DeltaMessages{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetDelta returns the changes to objects of any type since the given cursor
+func (api *SyncEngineAPI) GetDelta(accountID string, cursor string, options DeltaOptions) (*Deltas, error) {
+	return api.GetDeltaContext(context.Background(), accountID, cursor, options)
+}
+
+// GetDeltaContext returns the changes to objects of any type since the given cursor using the given context
+func (api *SyncEngineAPI) GetDeltaContext(ctx context.Context, accountID string, cursor string, options DeltaOptions) (*Deltas, error) {
+	var resp *http.Response
+	var err error
+
+	if resp, err = api.executeRequest(ctx, http.MethodGet, accountID, withQuery("/delta", options.encode(cursor)), nil); err != nil {
+		return nil, err
+	}
+
+	var result = &Deltas{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// longPollGracePeriod is how long a long poll may run past its timeout before it is abandoned
+var longPollGracePeriod = 5 * time.Second
+
+// timeoutSeconds converts a timeout to the whole seconds expected by the sync engine, rounding up
+func timeoutSeconds(timeout time.Duration) int {
+	return int((timeout + time.Second - 1) / time.Second)
+}
+
+// LongPollDelta waits until changes are available after the given cursor, or until the timeout expires,
+// and returns them. The next cursor to poll from is the CursorEnd of the returned deltas.
+// The call is bound by the context and the timeout (rounded up to whole seconds) rather than by the client timeout.
+func (api *SyncEngineAPI) LongPollDelta(ctx context.Context, accountID string, cursor string, timeout time.Duration) (*Deltas, error) {
+	return api.LongPollDeltaWithOptions(ctx, accountID, cursor, timeout, DeltaOptions{})
+}
+
+// LongPollDeltaWithOptions waits until changes matching the given options are available after the given cursor,
+// or until the timeout expires, and returns them
+func (api *SyncEngineAPI) LongPollDeltaWithOptions(ctx context.Context, accountID string, cursor string, timeout time.Duration, options DeltaOptions) (*Deltas, error) {
+	var resp *http.Response
+	var err error
+
+	var seconds = timeoutSeconds(timeout)
+	if seconds > 0 {
+		// Give up on servers that stall past the timeout
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(seconds)*time.Second+longPollGracePeriod)
+		defer cancel()
+	}
+
+	var values = options.values(cursor)
+	values.Set("timeout", strconv.Itoa(seconds))
+
+	var request = apiRequest{
+		method:    http.MethodGet,
+		userID:    accountID,
+		path:      withQuery("/delta/longpoll", values.Encode()),
+		longLived: true,
+	}
+
+	if resp, err = api.do(ctx, request); err != nil {
+		return nil, err
+	}
+
+	var result = &Deltas{}
+	if err = decodeResponse(resp, result); err != nil {
+		return nil, err
 	}
 
 	return result, nil
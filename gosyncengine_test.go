@@ -1,9 +1,19 @@
 package gosyncengine
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/maxcnunes/httpfake"
 )
@@ -76,6 +86,56 @@ func TestGetAccounts(t *testing.T) {
 	}
 }
 
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests = append(rt.requests, req)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestClientOptions(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/accounts").
+		Reply(200).
+		BodyString(`[]`)
+
+	transport := &recordingTransport{}
+	client := New(fakeService.ResolveURL(""),
+		WithTransport(transport),
+		WithTimeout(5*time.Second),
+		WithUserAgent("test-agent"))
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetAccounts(); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if len(transport.requests) != 2 {
+		t.Errorf("Expected 2 requests to go through the transport, got %d", len(transport.requests))
+	}
+
+	for _, req := range transport.requests {
+		if ua := req.Header.Get("User-Agent"); ua != "test-agent" {
+			t.Errorf("Unexpected User-Agent: %s", ua)
+		}
+	}
+}
+
+func TestWithHTTPClientNotModified(t *testing.T) {
+	httpClient := &http.Client{}
+	New("http://localhost", WithHTTPClient(httpClient), WithTimeout(time.Second))
+
+	if httpClient.Timeout != 0 {
+		t.Error("WithTimeout should not modify the client passed to WithHTTPClient")
+	}
+}
+
 func TestGetAccountsNot200(t *testing.T) {
 	fakeService := httpfake.New()
 	defer fakeService.Server.Close()
@@ -87,6 +147,38 @@ func TestGetAccountsNot200(t *testing.T) {
 	client := New(fakeService.ResolveURL(""))
 	if _, err := client.GetAccounts(); err == nil {
 		t.Error("Should have gotten an error here")
+	} else if !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestGetThreadsAPIError(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/threads").
+		Reply(403).
+		BodyString(`{"type": "invalid_request_error", "message": "Account is not allowed"}`)
+
+	client := New(fakeService.ResolveURL(""))
+	_, err := client.GetThreads("aaa")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+
+	if apiErr.StatusCode != 403 || apiErr.Method != "GET" || apiErr.Path != "/threads" {
+		t.Errorf("Unexpected APIError request details: %+v", apiErr)
+	}
+
+	if apiErr.Type != "invalid_request_error" || apiErr.Message != "Account is not allowed" {
+		t.Errorf("Unexpected APIError payload: %+v", apiErr)
+	}
+
+	if !IsForbidden(err) || IsNotFound(err) || IsUnauthorized(err) || IsRetryable(err) {
+		t.Errorf("Unexpected error classification for %v", err)
 	}
 }
 
@@ -197,6 +289,24 @@ func TestGetThreads(t *testing.T) {
 	}
 }
 
+func TestGetThreadsContextCanceled(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/threads").
+		Reply(200).
+		BodyString(`[]`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := New(fakeService.ResolveURL(""))
+	if _, err := client.GetThreadsContext(ctx, "aaa"); err == nil {
+		t.Error("Should have failed on a canceled context")
+	}
+}
+
 func TestGetThreadsNot200(t *testing.T) {
 	fakeService := httpfake.New()
 	defer fakeService.Server.Close()
@@ -312,6 +422,68 @@ func TestGetThreadByIDBadUnmarshaling(t *testing.T) {
 	}
 }
 
+func TestUpdateThread(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/threads/aaa" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"id": "aaa", "object": "thread", "unread": false, "starred": true}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	var thread *Thread
+	var err error
+	if thread, err = client.UpdateThread("zzz", "aaa", ThreadUpdate{Unread: Bool(false), Starred: Bool(true)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if thread.Unread || !thread.Starred {
+		t.Errorf("Unexpected UpdateThread result: %v", thread)
+	}
+
+	if len(received) != 2 || received["unread"] != false || received["starred"] != true {
+		t.Errorf("Unexpected UpdateThread request: %v", received)
+	}
+
+	if _, err = client.MoveThreadToFolder("zzz", "aaa", "fff"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(received) != 1 || received["folder_id"] != "fff" {
+		t.Errorf("Unexpected MoveThreadToFolder request: %v", received)
+	}
+
+	if _, err = client.SetThreadLabels("zzz", "aaa", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if labelIDs, ok := received["label_ids"].([]interface{}); len(received) != 1 || !ok || len(labelIDs) != 0 {
+		t.Errorf("Unexpected SetThreadLabels request: %v", received)
+	}
+}
+
+func TestUpdateThreadNot200(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Put("/threads/aaa").
+		Reply(404)
+
+	client := New(fakeService.ResolveURL(""))
+
+	if _, err := client.MarkThreadRead("zzz", "aaa"); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
 func TestGetMessageByID(t *testing.T) {
 	fakeService := httpfake.New()
 	defer fakeService.Server.Close()
@@ -393,6 +565,142 @@ func TestGetMessageByIDBadUnmarshaling(t *testing.T) {
 	}
 }
 
+func TestGetExpandedMessageByID(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/messages/aaa").
+		Reply(200).
+		BodyString(`{
+        "account_id": "zzz",
+        "cc": [
+            {
+                "email": "c@d.com",
+                "name": "c d"
+            }
+        ],
+        "date": 1500437314,
+        "events": [
+            {
+                "id": "eee",
+                "object": "event",
+                "title": "Lunch",
+                "when": {
+                    "object": "timespan",
+                    "start_time": 1500440000,
+                    "end_time": 1500443600
+                }
+            }
+        ],
+        "files": [
+            {
+                "content_type": "text/calendar",
+                "filename": "invite.ics",
+                "id": "fff",
+                "size": 1024
+            }
+        ],
+        "headers": {
+            "In-Reply-To": "<1233@somewhere.com>",
+            "Message-Id": "<1234@somewhere.com>",
+            "References": ["<1232@somewhere.com>", "<1233@somewhere.com>"]
+        },
+        "id": "aaa",
+        "labels": [
+            {
+                "display_name": "Important",
+                "id": "lll",
+                "name": "important"
+            }
+        ],
+        "object": "message",
+        "thread_id": "bbb"
+    }`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var message *Message
+	var err error
+	if message, err = client.GetExpandedMessageByID("zzz", "aaa"); err != nil {
+		t.Fatal(err)
+	}
+
+	if message.Headers == nil || message.Headers.MessageID != "<1234@somewhere.com>" || len(message.Headers.References) != 2 {
+		t.Errorf("Unexpected message headers: %v", message.Headers)
+	}
+
+	if len(message.CC) != 1 || len(message.Files) != 1 || len(message.Labels) != 1 {
+		t.Errorf("Unexpected message participants, files or labels: %v", message)
+	}
+
+	if len(message.Events) != 1 || message.Events[0].When.StartTime != 1500440000 {
+		t.Errorf("Unexpected message events: %v", message.Events)
+	}
+}
+
+func TestUpdateMessage(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/messages/aaa" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"id": "aaa", "object": "message", "unread": false, "starred": false}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	var message *Message
+	var err error
+	if message, err = client.MarkMessageRead("zzz", "aaa"); err != nil {
+		t.Fatal(err)
+	}
+
+	if message.ID != "aaa" || message.Unread {
+		t.Errorf("Unexpected MarkMessageRead result: %v", message)
+	}
+
+	if len(received) != 1 || received["unread"] != false {
+		t.Errorf("Unexpected MarkMessageRead request: %v", received)
+	}
+
+	if _, err = client.UpdateMessage("zzz", "aaa", MessageUpdate{Starred: Bool(true), LabelIDs: []string{"l1", "l2"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(received) != 2 || received["starred"] != true || len(received["label_ids"].([]interface{})) != 2 {
+		t.Errorf("Unexpected UpdateMessage request: %v", received)
+	}
+
+	if _, err = client.SetMessageLabels("zzz", "aaa", []string{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if labelIDs, ok := received["label_ids"].([]interface{}); len(received) != 1 || !ok || len(labelIDs) != 0 {
+		t.Errorf("Unexpected SetMessageLabels request: %v", received)
+	}
+}
+
+func TestMoveMessageNot200(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Put("/messages/aaa").
+		Reply(404)
+
+	client := New(fakeService.ResolveURL(""))
+
+	if _, err := client.MoveMessage("zzz", "aaa", "fff"); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
 func TestGetThreadMessages(t *testing.T) {
 	fakeService := httpfake.New()
 	defer fakeService.Server.Close()
@@ -493,6 +801,581 @@ func TestGetThreadMessagesNot200(t *testing.T) {
 	}
 }
 
+func TestGetMessages(t *testing.T) {
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages/" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		received = r.URL.Query()
+
+		w.Write([]byte(`[{
+        "account_id": "zzz",
+        "id": "aaa1",
+        "object": "message",
+        "subject": "The best Email. Ever.",
+        "thread_id": "aaa",
+        "unread": true
+    }]`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	var messages Messages
+	var err error
+	if messages, err = client.GetMessages("zzz", NewMessageQuery().In("inbox").Unread(true).Offset(20).Limit(10)); err != nil {
+		t.Error(err)
+	} else if len(messages) != 1 || messages[0].ID != "aaa1" {
+		t.Errorf("Unexpected GetMessages result: %v", messages)
+	}
+
+	if received.Get("in") != "inbox" || received.Get("unread") != "true" || received.Get("offset") != "20" || received.Get("limit") != "10" {
+		t.Errorf("Unexpected GetMessages request: %v", received)
+	}
+}
+
+func TestGetMessagesNot200(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/messages/").
+		Reply(404)
+
+	client := New(fakeService.ResolveURL(""))
+
+	if _, err := client.GetMessages("zzz", nil); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestGetDraftByID(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/drafts/ddd").
+		Reply(200).
+		BodyString(`{
+        "account_id": "zzz",
+        "body": "<html><body>Hello</body></html>",
+        "date": 1500437314,
+        "id": "ddd",
+        "object": "draft",
+        "reply_to_message_id": "mmm",
+        "subject": "Re: Hello",
+        "thread_id": "ttt",
+        "to": [
+            {
+                "email": "a@b.com",
+                "name": "a b"
+            }
+        ],
+        "version": 3
+    }`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var draft *Draft
+	var err error
+	if draft, err = client.GetDraftByID("zzz", "ddd"); err != nil {
+		t.Error(err)
+	} else if draft.ID != "ddd" || draft.Version != 3 || draft.ReplyToMessageID != "mmm" || len(draft.To) != 1 {
+		t.Errorf("Unexpected GetDraftByID result: %v", draft)
+	}
+}
+
+func TestCreateDraft(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Post("/drafts").
+		Reply(200).
+		BodyString(`{"id": "ddd", "object": "draft", "subject": "Hello", "version": 0}`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var draft *Draft
+	var err error
+	if draft, err = client.CreateDraft("zzz", OutboundMessage{Subject: "Hello", To: []Participant{{Email: "a@b.com"}}}); err != nil {
+		t.Error(err)
+	} else if draft.ID != "ddd" || draft.Subject != "Hello" {
+		t.Errorf("Unexpected CreateDraft result: %v", draft)
+	}
+}
+
+func TestUpdateDraft(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/drafts/ddd" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Write([]byte(`{"id": "ddd", "object": "draft", "subject": "Hello", "version": 2}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	var draft *Draft
+	var err error
+	if draft, err = client.UpdateDraft("zzz", "ddd", 1, OutboundMessage{Subject: "Hello", To: []Participant{{Email: "a@b.com"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if draft.Version != 2 {
+		t.Errorf("Unexpected UpdateDraft result: %v", draft)
+	}
+
+	// Recipients and content missing from the update are cleared rather than left unchanged
+	if cc, ok := received["cc"].([]interface{}); !ok || len(cc) != 0 {
+		t.Errorf("Expected the CC list to be cleared, got %v", received)
+	}
+
+	if to := received["to"].([]interface{}); len(to) != 1 || received["version"] != float64(1) || received["body"] != "" {
+		t.Errorf("Unexpected UpdateDraft request: %v", received)
+	}
+
+	if _, ok := received["file_ids"].([]interface{}); !ok {
+		t.Errorf("Expected the file IDs to be cleared, got %v", received)
+	}
+}
+
+func TestUpdateDraftVersionConflict(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"type": "invalid_request_error", "message": "Draft ddd.1 has already been updated to version 2"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	_, err := client.UpdateDraft("zzz", "ddd", 1, OutboundMessage{Subject: "Hello"})
+	if !IsVersionConflict(err) {
+		t.Fatalf("Expected a version conflict error, got %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Errorf("The version conflict should wrap the APIError, got %v", err)
+	}
+
+	if received["version"] != float64(1) || received["subject"] != "Hello" {
+		t.Errorf("Unexpected UpdateDraft request: %v", received)
+	}
+
+	if err = client.DeleteDraft("zzz", "ddd", 1); !IsVersionConflict(err) {
+		t.Errorf("Expected a version conflict error, got %v", err)
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Post("/send").
+		Reply(200).
+		BodyString(`{"id": "mmm", "object": "message", "subject": "Hello", "thread_id": "ttt"}`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var message *Message
+	var err error
+	if message, err = client.SendMessage("zzz", OutboundMessage{Subject: "Hello", To: []Participant{{Email: "a@b.com"}}}); err != nil {
+		t.Error(err)
+	} else if message.ID != "mmm" || message.ThreadID != "ttt" {
+		t.Errorf("Unexpected SendMessage result: %v", message)
+	}
+}
+
+func TestSendDraftFailure(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&received)
+		w.WriteHeader(http.StatusPaymentRequired)
+		w.Write([]byte(`{"type": "api_error", "message": "Sending to all recipients failed", "server_error": "550 5.1.1 User unknown"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	_, err := client.SendDraft("zzz", "ddd", 2)
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an APIError, got %v", err)
+	}
+
+	if apiErr.ServerError != "550 5.1.1 User unknown" || !strings.Contains(err.Error(), "User unknown") {
+		t.Errorf("The provider error should be surfaced, got %v", err)
+	}
+
+	if received["draft_id"] != "ddd" || received["version"] != float64(2) {
+		t.Errorf("Unexpected SendDraft request: %v", received)
+	}
+}
+
+func TestSendRawMIME(t *testing.T) {
+	const mime = "From: a@b.com\r\nTo: c@d.com\r\nSubject: Hello\r\n\r\nHello World\r\n"
+
+	var contentType, received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ := ioutil.ReadAll(r.Body)
+		received = string(body)
+		w.Write([]byte(`{"id": "mmm", "object": "message", "subject": "Hello"}`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	var message *Message
+	var err error
+	if message, err = client.SendRawMIME("zzz", strings.NewReader(mime)); err != nil {
+		t.Fatal(err)
+	}
+
+	if message.ID != "mmm" {
+		t.Errorf("Unexpected SendRawMIME result: %v", message)
+	}
+
+	if contentType != "message/rfc822" || received != mime {
+		t.Errorf("Unexpected SendRawMIME request: %s %q", contentType, received)
+	}
+}
+
+// slowReader delays every read to simulate a slow upload
+type slowReader struct {
+	r     io.Reader
+	delay time.Duration
+}
+
+func (s slowReader) Read(p []byte) (int, error) {
+	time.Sleep(s.delay)
+	return s.r.Read(p)
+}
+
+func TestSendRawMIMESlowTransfer(t *testing.T) {
+	const mime = "From: a@b.com\r\nTo: c@d.com\r\nSubject: Hello\r\n\r\nHello World\r\n"
+
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		received = string(body)
+		w.Write([]byte(`{"id": "mmm", "object": "message", "subject": "Hello"}`))
+	}))
+	defer server.Close()
+
+	// The client timeout must not cut transfers short
+	client := New(server.URL, WithTimeout(50*time.Millisecond))
+
+	if _, err := client.SendRawMIME("zzz", slowReader{r: strings.NewReader(mime), delay: 100 * time.Millisecond}); err != nil {
+		t.Fatal(err)
+	}
+
+	if received != mime {
+		t.Errorf("Unexpected SendRawMIME request: %q", received)
+	}
+}
+
+func TestGetFileByID(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/files/fff").
+		Reply(200).
+		BodyString(`{
+        "account_id": "zzz",
+        "content_type": "text/plain",
+        "filename": "notes.txt",
+        "id": "fff",
+        "message_ids": ["mmm"],
+        "object": "file",
+        "size": 5
+    }`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var file *File
+	var err error
+	if file, err = client.GetFileByID("zzz", "fff"); err != nil {
+		t.Error(err)
+	} else if file.Filename != "notes.txt" || file.Size != 5 || len(file.MessageIDs) != 1 {
+		t.Errorf("Unexpected GetFileByID result: %v", file)
+	}
+}
+
+func TestDownloadFile(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/files/fff/download").
+		Reply(200).
+		BodyString(`notes`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var buffer bytes.Buffer
+	if n, err := client.DownloadFile("zzz", "fff", &buffer); err != nil {
+		t.Error(err)
+	} else if n != 5 || buffer.String() != "notes" {
+		t.Errorf("Unexpected DownloadFile result: %d %q", n, buffer.String())
+	}
+
+	if _, err := client.DownloadFile("zzz", "ggg", &buffer); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestDownloadFileSlowTransfer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("no"))
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("tes"))
+	}))
+	defer server.Close()
+
+	// The client timeout must not cut transfers short
+	client := New(server.URL, WithTimeout(50*time.Millisecond))
+
+	var buffer bytes.Buffer
+	if _, err := client.DownloadFile("zzz", "fff", &buffer); err != nil {
+		t.Error(err)
+	} else if buffer.String() != "notes" {
+		t.Errorf("Unexpected DownloadFile result: %q", buffer.String())
+	}
+}
+
+func TestUploadFile(t *testing.T) {
+	var filename, content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		data, _ := ioutil.ReadAll(file)
+		filename, content = header.Filename, string(data)
+		w.Write([]byte(`[{"id": "fff", "object": "file", "filename": "notes.txt", "size": 5}]`))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	var file *File
+	var err error
+	if file, err = client.UploadFile("zzz", "notes.txt", strings.NewReader("notes")); err != nil {
+		t.Fatal(err)
+	}
+
+	if file.ID != "fff" {
+		t.Errorf("Unexpected UploadFile result: %v", file)
+	}
+
+	if filename != "notes.txt" || content != "notes" {
+		t.Errorf("Unexpected UploadFile request: %s %q", filename, content)
+	}
+}
+
+func TestGetFolders(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/folders").
+		Reply(200).
+		BodyString(`[
+    {
+        "account_id": "5qro12wr9mojq8y9y8f6cdcp",
+        "display_name": "INBOX",
+        "id": "a9n0r8jfqc8v6vih7tfeykve5",
+        "name": "inbox",
+        "object": "folder"
+    },
+    {
+        "account_id": "5qro12wr9mojq8y9y8f6cdcp",
+        "display_name": "[Gmail]/All Mail",
+        "id": "917ucyqu8q9rmabkax89v3wyi",
+        "name": null,
+        "object": "folder"
+    }]`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var folders Folders
+	var err error
+	if folders, err = client.GetFolders("aaa"); err != nil {
+		t.Error(err)
+	} else if len(folders) != 2 || folders[0].Name != "inbox" {
+		t.Errorf("Unexpected GetFolders result: %v", folders)
+	}
+}
+
+func TestGetFolderByIDNot200(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/folders/aaa").
+		Reply(404)
+
+	client := New(fakeService.ResolveURL(""))
+
+	if _, err := client.GetFolderByID("aaa", "aaa"); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestCreateFolder(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Post("/folders").
+		Reply(200).
+		BodyString(`{"account_id": "aaa", "display_name": "Receipts", "id": "bbb", "name": null, "object": "folder"}`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var folder *Folder
+	var err error
+	if folder, err = client.CreateFolder("aaa", "Receipts"); err != nil {
+		t.Error(err)
+	} else if folder.ID != "bbb" || folder.DisplayName != "Receipts" {
+		t.Errorf("Unexpected CreateFolder result: %v", folder)
+	}
+}
+
+func TestUpdateFolder(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Put("/folders/bbb").
+		Reply(200).
+		BodyString(`{"account_id": "aaa", "display_name": "Invoices", "id": "bbb", "name": null, "object": "folder"}`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var folder *Folder
+	var err error
+	if folder, err = client.UpdateFolder("aaa", "bbb", "Invoices"); err != nil {
+		t.Error(err)
+	} else if folder.DisplayName != "Invoices" {
+		t.Errorf("Unexpected UpdateFolder result: %v", folder)
+	}
+}
+
+func TestDeleteFolder(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Delete("/folders/bbb").
+		Reply(200)
+
+	client := New(fakeService.ResolveURL(""))
+
+	if err := client.DeleteFolder("aaa", "bbb"); err != nil {
+		t.Error(err)
+	}
+
+	if err := client.DeleteFolder("aaa", "ccc"); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestGetLabels(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/labels").
+		Reply(200).
+		BodyString(`[
+    {
+        "account_id": "5qro12wr9mojq8y9y8f6cdcp",
+        "display_name": "Important",
+        "id": "4fl4kr3jgc9b9xs7ug0s2wjaq",
+        "name": "important",
+        "object": "label"
+    }]`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var labels Labels
+	var err error
+	if labels, err = client.GetLabels("aaa"); err != nil {
+		t.Error(err)
+	} else if len(labels) != 1 || labels[0].Name != "important" {
+		t.Errorf("Unexpected GetLabels result: %v", labels)
+	}
+}
+
+func TestCreateLabel(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Post("/labels").
+		Reply(200).
+		BodyString(`{"account_id": "aaa", "display_name": "Receipts", "id": "bbb", "name": null, "object": "label"}`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	var label *Label
+	var err error
+	if label, err = client.CreateLabel("aaa", "Receipts"); err != nil {
+		t.Error(err)
+	} else if label.ID != "bbb" || label.Object != "label" {
+		t.Errorf("Unexpected CreateLabel result: %v", label)
+	}
+}
+
+func TestCategoriesFollowOrganizationUnit(t *testing.T) {
+	fakeService := httpfake.New()
+	defer fakeService.Server.Close()
+
+	fakeService.NewHandler().
+		Get("/labels").
+		Reply(200).
+		BodyString(`[{"display_name": "Important", "id": "lll", "name": "important", "object": "label"}]`)
+
+	fakeService.NewHandler().
+		Get("/folders").
+		Reply(200).
+		BodyString(`[{"display_name": "INBOX", "id": "fff", "name": "inbox", "object": "folder"}]`)
+
+	client := New(fakeService.ResolveURL(""))
+
+	gmail := Account{AccountID: "aaa", OrganizationUnit: OrganizationUnitLabel}
+	if categories, err := client.GetCategories(gmail); err != nil {
+		t.Error(err)
+	} else if len(categories) != 1 || categories[0].Object != "label" {
+		t.Errorf("Expected labels for a label based account, got %v", categories)
+	}
+
+	imap := Account{AccountID: "bbb", OrganizationUnit: OrganizationUnitFolder}
+	if categories, err := client.GetCategories(imap); err != nil {
+		t.Error(err)
+	} else if len(categories) != 1 || categories[0].Object != "folder" {
+		t.Errorf("Expected folders for a folder based account, got %v", categories)
+	}
+}
+
 func TestGetDeltaLatestCursor(t *testing.T) {
 	baseURL := getEnvValue("SYNCENGINE_URL")
 	client := New(baseURL)
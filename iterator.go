@@ -0,0 +1,274 @@
+package gosyncengine
+
+import (
+	"context"
+)
+
+// DefaultPageSize is the number of objects requested per page by iterators
+const DefaultPageSize = 100
+
+// ThreadIterator walks through all the threads of a listing, fetching pages as needed
+type ThreadIterator struct {
+	ctx      context.Context
+	fetch    func(ctx context.Context, offset int, limit int) (Threads, error)
+	pageSize int
+	offset   int
+	page     Threads
+	index    int
+	done     bool
+	err      error
+}
+
+func newThreadIterator(ctx context.Context, offset int, pageSize int, fetch func(ctx context.Context, offset int, limit int) (Threads, error)) *ThreadIterator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return &ThreadIterator{
+		ctx:      ctx,
+		fetch:    fetch,
+		pageSize: pageSize,
+		offset:   offset,
+		index:    -1,
+	}
+}
+
+// IterateThreads returns an iterator over all the threads of the specified account ID
+func (api *SyncEngineAPI) IterateThreads(ctx context.Context, accountID string, pageSize int) *ThreadIterator {
+	return api.IterateThreadsWithQuery(ctx, accountID, NewThreadQuery().Limit(pageSize))
+}
+
+// IterateThreadsWithQuery returns an iterator over all the threads of the specified account ID matching the given query.
+// The query's limit is used as the page size and its offset as the starting point.
+func (api *SyncEngineAPI) IterateThreadsWithQuery(ctx context.Context, accountID string, query *ThreadQuery) *ThreadIterator {
+	query = query.clone()
+
+	return newThreadIterator(ctx, query.getInt("offset"), query.getInt("limit"), func(ctx context.Context, offset int, limit int) (Threads, error) {
+		return api.GetThreadsWithQueryContext(ctx, accountID, query.Offset(offset).Limit(limit))
+	})
+}
+
+// Next advances the iterator to the next thread, fetching the next page if needed.
+// It returns false when there are no more threads or an error occurred.
+func (it *ThreadIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+	if it.index < len(it.page) {
+		return true
+	}
+
+	if it.done {
+		return false
+	}
+
+	var page Threads
+	if page, it.err = it.fetch(it.ctx, it.offset, it.pageSize); it.err != nil {
+		return false
+	}
+
+	it.offset += len(page)
+	it.page = page
+	it.index = 0
+
+	// A short page is the last one
+	if len(page) < it.pageSize {
+		it.done = true
+	}
+
+	return len(page) > 0
+}
+
+// Thread returns the current thread
+func (it *ThreadIterator) Thread() *Thread {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+
+	return &it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *ThreadIterator) Err() error {
+	return it.err
+}
+
+// MessageIterator walks through all the messages of a listing, fetching pages as needed
+type MessageIterator struct {
+	ctx      context.Context
+	fetch    func(ctx context.Context, offset int, limit int) (Messages, error)
+	pageSize int
+	offset   int
+	page     Messages
+	index    int
+	done     bool
+	err      error
+}
+
+func newMessageIterator(ctx context.Context, offset int, pageSize int, fetch func(ctx context.Context, offset int, limit int) (Messages, error)) *MessageIterator {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	return &MessageIterator{
+		ctx:      ctx,
+		fetch:    fetch,
+		pageSize: pageSize,
+		offset:   offset,
+		index:    -1,
+	}
+}
+
+// IterateMessages returns an iterator over all the messages of the specified account ID matching the given query.
+// The query's limit is used as the page size and its offset as the starting point.
+func (api *SyncEngineAPI) IterateMessages(ctx context.Context, accountID string, query *MessageQuery) *MessageIterator {
+	query = query.clone()
+
+	return newMessageIterator(ctx, query.getInt("offset"), query.getInt("limit"), func(ctx context.Context, offset int, limit int) (Messages, error) {
+		return api.GetMessagesContext(ctx, accountID, query.Offset(offset).Limit(limit))
+	})
+}
+
+// IterateThreadMessages returns an iterator over all the messages associated with the specified thread ID
+func (api *SyncEngineAPI) IterateThreadMessages(ctx context.Context, accountID string, threadID string, pageSize int) *MessageIterator {
+	return api.IterateMessages(ctx, accountID, NewMessageQuery().ThreadID(threadID).Limit(pageSize))
+}
+
+// Next advances the iterator to the next message, fetching the next page if needed.
+// It returns false when there are no more messages or an error occurred.
+func (it *MessageIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	it.index++
+	if it.index < len(it.page) {
+		return true
+	}
+
+	if it.done {
+		return false
+	}
+
+	var page Messages
+	if page, it.err = it.fetch(it.ctx, it.offset, it.pageSize); it.err != nil {
+		return false
+	}
+
+	it.offset += len(page)
+	it.page = page
+	it.index = 0
+
+	// A short page is the last one
+	if len(page) < it.pageSize {
+		it.done = true
+	}
+
+	return len(page) > 0
+}
+
+// Message returns the current message
+func (it *MessageIterator) Message() *Message {
+	if it.index < 0 || it.index >= len(it.page) {
+		return nil
+	}
+
+	return &it.page[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *MessageIterator) Err() error {
+	return it.err
+}
+
+// DeltaIterator drains all the pending delta pages after a cursor, one page at a time
+type DeltaIterator struct {
+	ctx       context.Context
+	api       *SyncEngineAPI
+	accountID string
+	options   DeltaOptions
+	store     CursorStore
+	cursor    string
+	page      *Deltas
+	done      bool
+	err       error
+}
+
+// IterateDelta returns an iterator over the pending delta pages after the given cursor
+func (api *SyncEngineAPI) IterateDelta(ctx context.Context, accountID string, cursor string, options DeltaOptions) *DeltaIterator {
+	return &DeltaIterator{
+		ctx:       ctx,
+		api:       api,
+		accountID: accountID,
+		options:   options,
+		cursor:    cursor,
+	}
+}
+
+// IterateDeltaFromStore returns an iterator over the pending delta pages after the cursor saved in store
+// (or the latest cursor if none was saved). Pages are checkpointed only when acknowledged with Ack.
+func (api *SyncEngineAPI) IterateDeltaFromStore(ctx context.Context, accountID string, store CursorStore, options DeltaOptions) (*DeltaIterator, error) {
+	var cursor string
+	var err error
+
+	if cursor, err = api.loadCursor(ctx, accountID, store); err != nil {
+		return nil, err
+	}
+
+	it := api.IterateDelta(ctx, accountID, cursor, options)
+	it.store = store
+
+	return it, nil
+}
+
+// Next fetches the next page of deltas. It returns false once the iterator caught up
+// with the latest changes or an error occurred.
+func (it *DeltaIterator) Next() bool {
+	if it.err != nil || it.done {
+		return false
+	}
+
+	var page *Deltas
+	if page, it.err = it.api.GetDeltaContext(it.ctx, it.accountID, it.cursor, it.options); it.err != nil {
+		it.page = nil
+		return false
+	}
+
+	if len(page.Deltas) == 0 || page.CursorEnd == "" || page.CursorEnd == it.cursor {
+		it.page = nil
+		it.done = true
+		return false
+	}
+
+	it.page = page
+	it.cursor = page.CursorEnd
+
+	return true
+}
+
+// Delta returns the current page of deltas
+func (it *DeltaIterator) Delta() *Deltas {
+	return it.page
+}
+
+// Cursor returns the cursor following the current page, which is where a later iteration should resume from
+func (it *DeltaIterator) Cursor() string {
+	return it.cursor
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *DeltaIterator) Err() error {
+	return it.err
+}
+
+// Ack checkpoints the cursor following the current page in the iterator's cursor store, once the page was handled.
+// It does nothing for iterators created without a cursor store.
+func (it *DeltaIterator) Ack() error {
+	if it.store == nil || it.page == nil {
+		return nil
+	}
+
+	return it.store.Save(it.accountID, it.cursor)
+}
@@ -0,0 +1,171 @@
+package gosyncengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// newPagedServer serves count objects at path, honoring the offset and limit query parameters
+func newPagedServer(t *testing.T, path string, count int) (*httptest.Server, *int) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		requests++
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		page := []map[string]string{}
+		for i := offset; i < count && i < offset+limit; i++ {
+			page = append(page, map[string]string{"id": fmt.Sprintf("id%d", i)})
+		}
+
+		if err := json.NewEncoder(w).Encode(page); err != nil {
+			t.Error(err)
+		}
+	}))
+
+	return server, &requests
+}
+
+func TestThreadIterator(t *testing.T) {
+	server, requests := newPagedServer(t, "/threads", 5)
+	defer server.Close()
+
+	client := New(server.URL)
+	it := client.IterateThreads(context.Background(), "aaa", 2)
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Thread().ID)
+	}
+
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+
+	if len(ids) != 5 || ids[0] != "id0" || ids[4] != "id4" {
+		t.Errorf("Unexpected threads: %v", ids)
+	}
+
+	if *requests != 3 {
+		t.Errorf("Expected 3 page requests, got %d", *requests)
+	}
+}
+
+func TestMessageIteratorExactPages(t *testing.T) {
+	server, requests := newPagedServer(t, "/messages/", 4)
+	defer server.Close()
+
+	client := New(server.URL)
+	it := client.IterateThreadMessages(context.Background(), "aaa", "bbb", 2)
+
+	var count int
+	for it.Next() {
+		count++
+	}
+
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+
+	if count != 4 || *requests != 3 {
+		t.Errorf("Expected 4 messages in 3 requests, got %d messages in %d requests", count, *requests)
+	}
+}
+
+func TestMessageIteratorWithQuery(t *testing.T) {
+	server, requests := newPagedServer(t, "/messages/", 7)
+	defer server.Close()
+
+	client := New(server.URL)
+	it := client.IterateMessages(context.Background(), "aaa", NewMessageQuery().Unread(true).Offset(2).Limit(3))
+
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Message().ID)
+	}
+
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+
+	if len(ids) != 5 || ids[0] != "id2" || *requests != 2 {
+		t.Errorf("Unexpected messages %v in %d requests", ids, *requests)
+	}
+}
+
+func TestThreadIteratorError(t *testing.T) {
+	server, _ := newPagedServer(t, "/other", 0)
+	defer server.Close()
+
+	client := New(server.URL)
+	it := client.IterateThreads(context.Background(), "aaa", 0)
+
+	if it.Next() {
+		t.Error("Should not have gotten any thread")
+	}
+
+	if !IsNotFound(it.Err()) {
+		t.Errorf("Expected a not found error, got %v", it.Err())
+	}
+}
+
+// newDeltaServer serves a delta page per cursor, returning an empty page once caught up
+func newDeltaServer(t *testing.T, pages map[string][]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/delta" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		cursor := r.URL.Query().Get("cursor")
+		result := Deltas{CursorStart: cursor, CursorEnd: cursor, Deltas: []Delta{}}
+		for _, next := range pages[cursor] {
+			result.Deltas = append(result.Deltas, Delta{Cursor: next, Event: DeltaEventDelete, Object: ObjectMessage, ID: next})
+			result.CursorEnd = next
+		}
+
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Error(err)
+		}
+	}))
+}
+
+func TestDeltaIterator(t *testing.T) {
+	server := newDeltaServer(t, map[string][]string{
+		"c0": {"c1", "c2"},
+		"c2": {"c3"},
+	})
+	defer server.Close()
+
+	client := New(server.URL)
+	it := client.IterateDelta(context.Background(), "zzz", "c0", DeltaOptions{})
+
+	var cursors []string
+	var count int
+	for it.Next() {
+		count += len(it.Delta().Deltas)
+		cursors = append(cursors, it.Cursor())
+	}
+
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+
+	if count != 3 || len(cursors) != 2 || cursors[0] != "c2" || cursors[1] != "c3" {
+		t.Errorf("Unexpected iteration: %d deltas, cursors %v", count, cursors)
+	}
+
+	if it.Cursor() != "c3" || it.Next() {
+		t.Errorf("The iterator should stay caught up at c3, got %s", it.Cursor())
+	}
+}
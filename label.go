@@ -0,0 +1,13 @@
+package gosyncengine
+
+// Label contains all the details on a specific label of an account organized by labels (such as Gmail)
+type Label struct {
+	ID          string `json:"id"`
+	AccountID   string `json:"account_id"`
+	Object      string `json:"object"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// Labels is a collection of Label objects
+type Labels []Label
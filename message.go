@@ -1,28 +1,78 @@
 package gosyncengine
 
+import "encoding/json"
+
 // Message contains all the details of a single message
 type Message struct {
-	ID        string        `json:"id"`
-	AccountID string        `json:"account_id"`
-	Object    string        `json:"object"`
-	ThreadID  string        `json:"thread_id"`
-	Date      int           `json:"date"`
-	From      []Participant `json:"from"`
-	To        []Participant `json:"to"`
-	BCC       []Participant `json:"bcc"`
-	Subject   string        `json:"subject"`
-	Snippet   string        `json:"snippet"`
-	Body      string        `json:"body"`
-	Folder    Folder        `json:"folder"`
-	ReplyTo   []Participant `json:"reply_to"`
-	Starred   bool          `json:"starred"`
-	Unread    bool          `json:"unread"`
+	ID               string          `json:"id"`
+	AccountID        string          `json:"account_id"`
+	Object           string          `json:"object"`
+	ThreadID         string          `json:"thread_id"`
+	Date             Timestamp       `json:"date"`
+	From             []Participant   `json:"from"`
+	To               []Participant   `json:"to"`
+	CC               []Participant   `json:"cc"`
+	BCC              []Participant   `json:"bcc"`
+	Subject          string          `json:"subject"`
+	Snippet          string          `json:"snippet"`
+	Body             string          `json:"body"`
+	Folder           Folder          `json:"folder"`
+	Labels           []Label         `json:"labels"`
+	Files            []File          `json:"files"`
+	Events           []Event         `json:"events"`
+	ReplyTo          []Participant   `json:"reply_to"`
+	Starred          bool            `json:"starred"`
+	Unread           bool            `json:"unread"`
+	Headers          *MessageHeaders `json:"headers"`
+	Version          int             `json:"version"`
+	ReplyToMessageID string          `json:"reply_to_message_id"`
+}
+
+// MessageHeaders contains the headers returned for messages fetched with the expanded view
+type MessageHeaders struct {
+	MessageID  string   `json:"Message-Id"`
+	InReplyTo  string   `json:"In-Reply-To"`
+	References []string `json:"References"`
+}
+
+// MessageUpdate describes the changes to apply to a message. Nil fields are left unchanged,
+// while an empty LabelIDs removes all the labels of the message.
+type MessageUpdate struct {
+	Unread   *bool    `json:"unread,omitempty"`
+	Starred  *bool    `json:"starred,omitempty"`
+	FolderID *string  `json:"folder_id,omitempty"`
+	LabelIDs []string `json:"label_ids,omitempty"`
+}
+
+// MarshalJSON sends label_ids whenever LabelIDs is not nil, so that an empty list clears the labels
+func (u MessageUpdate) MarshalJSON() ([]byte, error) {
+	type messageUpdate MessageUpdate
+	var body = struct {
+		messageUpdate
+		LabelIDs *[]string `json:"label_ids,omitempty"`
+	}{messageUpdate: messageUpdate(u)}
+
+	if u.LabelIDs != nil {
+		body.LabelIDs = &u.LabelIDs
+	}
+
+	return json.Marshal(body)
 }
 
 // Messages is a list of message object
 type Messages []Message
 
-// DeltaMessage providers the message struct given on a delta
+// DeltaMessage providers the message struct given on a delta.
+// Deletes carry only the ID of the deleted message and leave Attributes empty.
 type DeltaMessage struct {
+	Cursor     string  `json:"cursor"`
+	Event      string  `json:"event"`
+	Object     string  `json:"object"`
+	ID         string  `json:"id"`
 	Attributes Message `json:"attributes"`
 }
+
+// IsDelete returns true if the delta reports a deleted message
+func (d DeltaMessage) IsDelete() bool {
+	return d.Event == DeltaEventDelete
+}
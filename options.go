@@ -0,0 +1,59 @@
+package gosyncengine
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultUserAgent is the User-Agent header sent when none was configured
+const DefaultUserAgent = "gosyncengine"
+
+// Option configures a SyncEngineAPI object created by New
+type Option func(*SyncEngineAPI)
+
+// WithHTTPClient sets the HTTP client used for all calls to the sync engine
+func WithHTTPClient(client *http.Client) Option {
+	return func(api *SyncEngineAPI) {
+		api.client = client
+	}
+}
+
+// WithTimeout sets the overall timeout of a single call to the sync engine
+func WithTimeout(timeout time.Duration) Option {
+	return func(api *SyncEngineAPI) {
+		api.timeout = timeout
+	}
+}
+
+// WithTransport sets the transport used to issue HTTP requests (TLS configuration, proxies, recording transports, etc.)
+func WithTransport(transport http.RoundTripper) Option {
+	return func(api *SyncEngineAPI) {
+		api.transport = transport
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent on every call
+func WithUserAgent(userAgent string) Option {
+	return func(api *SyncEngineAPI) {
+		api.userAgent = userAgent
+	}
+}
+
+// buildClient creates the shared HTTP client once all options were applied.
+// A client passed via WithHTTPClient is copied rather than modified.
+func (api *SyncEngineAPI) buildClient() {
+	var client http.Client
+	if api.client != nil {
+		client = *api.client
+	}
+
+	if api.timeout > 0 {
+		client.Timeout = api.timeout
+	}
+
+	if api.transport != nil {
+		client.Transport = api.transport
+	}
+
+	api.client = &client
+}
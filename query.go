@@ -0,0 +1,333 @@
+package gosyncengine
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// query holds the URL parameters shared by the thread and message query builders
+type query struct {
+	values url.Values
+}
+
+func newQuery() query {
+	return query{values: url.Values{}}
+}
+
+// set stores a parameter, creating the values lazily so that the zero value of a query is usable
+func (q *query) set(key string, value string) {
+	if q.values == nil {
+		q.values = url.Values{}
+	}
+
+	q.values.Set(key, value)
+}
+
+func (q *query) setString(key string, value string) {
+	q.set(key, value)
+}
+
+func (q *query) setStrings(key string, values []string) {
+	q.set(key, strings.Join(values, ","))
+}
+
+func (q *query) setBool(key string, value bool) {
+	q.set(key, strconv.FormatBool(value))
+}
+
+func (q *query) setInt(key string, value int) {
+	q.set(key, strconv.Itoa(value))
+}
+
+func (q *query) setTimestamp(key string, value Timestamp) {
+	q.set(key, value.queryValue())
+}
+
+func (q query) getInt(key string) int {
+	value, _ := strconv.Atoi(q.values.Get(key))
+	return value
+}
+
+func (q query) clone() query {
+	values := url.Values{}
+	for key, value := range q.values {
+		values[key] = append([]string(nil), value...)
+	}
+
+	return query{values: values}
+}
+
+// withQuery appends an encoded query string to path
+func withQuery(path string, encoded string) string {
+	if encoded == "" {
+		return path
+	}
+
+	if strings.Contains(path, "?") {
+		return path + "&" + encoded
+	}
+
+	return path + "?" + encoded
+}
+
+// ThreadQuery builds the filters accepted by the sync engine's /threads endpoint
+type ThreadQuery struct {
+	query
+}
+
+// NewThreadQuery creates an empty thread query
+func NewThreadQuery() *ThreadQuery {
+	return &ThreadQuery{query: newQuery()}
+}
+
+// Subject matches threads with the given subject
+func (q *ThreadQuery) Subject(subject string) *ThreadQuery {
+	q.setString("subject", subject)
+	return q
+}
+
+// AnyEmail matches threads with any of the given email addresses in any of the participant fields
+func (q *ThreadQuery) AnyEmail(emails ...string) *ThreadQuery {
+	q.setStrings("any_email", emails)
+	return q
+}
+
+// To matches threads with the given email address in the To field
+func (q *ThreadQuery) To(email string) *ThreadQuery {
+	q.setString("to", email)
+	return q
+}
+
+// From matches threads with the given email address in the From field
+func (q *ThreadQuery) From(email string) *ThreadQuery {
+	q.setString("from", email)
+	return q
+}
+
+// CC matches threads with the given email address in the CC field
+func (q *ThreadQuery) CC(email string) *ThreadQuery {
+	q.setString("cc", email)
+	return q
+}
+
+// BCC matches threads with the given email address in the BCC field
+func (q *ThreadQuery) BCC(email string) *ThreadQuery {
+	q.setString("bcc", email)
+	return q
+}
+
+// In matches threads in the given folder or label, by ID, name or display name
+func (q *ThreadQuery) In(folder string) *ThreadQuery {
+	q.setString("in", folder)
+	return q
+}
+
+// Unread matches threads by their unread state
+func (q *ThreadQuery) Unread(unread bool) *ThreadQuery {
+	q.setBool("unread", unread)
+	return q
+}
+
+// Starred matches threads by their starred state
+func (q *ThreadQuery) Starred(starred bool) *ThreadQuery {
+	q.setBool("starred", starred)
+	return q
+}
+
+// Filename matches threads with an attachment of the given file name
+func (q *ThreadQuery) Filename(filename string) *ThreadQuery {
+	q.setString("filename", filename)
+	return q
+}
+
+// LastMessageBefore matches threads whose last message was received before the given timestamp
+func (q *ThreadQuery) LastMessageBefore(t Timestamp) *ThreadQuery {
+	q.setTimestamp("last_message_before", t)
+	return q
+}
+
+// LastMessageAfter matches threads whose last message was received after the given timestamp
+func (q *ThreadQuery) LastMessageAfter(t Timestamp) *ThreadQuery {
+	q.setTimestamp("last_message_after", t)
+	return q
+}
+
+// StartedBefore matches threads whose first message was received before the given timestamp
+func (q *ThreadQuery) StartedBefore(t Timestamp) *ThreadQuery {
+	q.setTimestamp("started_before", t)
+	return q
+}
+
+// StartedAfter matches threads whose first message was received after the given timestamp
+func (q *ThreadQuery) StartedAfter(t Timestamp) *ThreadQuery {
+	q.setTimestamp("started_after", t)
+	return q
+}
+
+// LastMessageBetween matches threads whose last message was received between the given timestamps
+func (q *ThreadQuery) LastMessageBetween(after Timestamp, before Timestamp) *ThreadQuery {
+	return q.LastMessageAfter(after).LastMessageBefore(before)
+}
+
+// StartedBetween matches threads whose first message was received between the given timestamps
+func (q *ThreadQuery) StartedBetween(after Timestamp, before Timestamp) *ThreadQuery {
+	return q.StartedAfter(after).StartedBefore(before)
+}
+
+// Offset skips the given number of threads
+func (q *ThreadQuery) Offset(offset int) *ThreadQuery {
+	q.setInt("offset", offset)
+	return q
+}
+
+// Limit caps the number of threads returned
+func (q *ThreadQuery) Limit(limit int) *ThreadQuery {
+	q.setInt("limit", limit)
+	return q
+}
+
+// Encode returns the URL encoded form of the query
+func (q *ThreadQuery) Encode() string {
+	if q == nil {
+		return ""
+	}
+
+	return q.values.Encode()
+}
+
+func (q *ThreadQuery) clone() *ThreadQuery {
+	if q == nil {
+		return NewThreadQuery()
+	}
+
+	return &ThreadQuery{query: q.query.clone()}
+}
+
+// MessageQuery builds the filters accepted by the sync engine's /messages endpoint
+type MessageQuery struct {
+	query
+}
+
+// NewMessageQuery creates an empty message query
+func NewMessageQuery() *MessageQuery {
+	return &MessageQuery{query: newQuery()}
+}
+
+// ThreadID matches messages of the given thread
+func (q *MessageQuery) ThreadID(threadID string) *MessageQuery {
+	q.setString("thread_id", threadID)
+	return q
+}
+
+// Subject matches messages with the given subject
+func (q *MessageQuery) Subject(subject string) *MessageQuery {
+	q.setString("subject", subject)
+	return q
+}
+
+// AnyEmail matches messages with any of the given email addresses in any of the participant fields
+func (q *MessageQuery) AnyEmail(emails ...string) *MessageQuery {
+	q.setStrings("any_email", emails)
+	return q
+}
+
+// To matches messages with the given email address in the To field
+func (q *MessageQuery) To(email string) *MessageQuery {
+	q.setString("to", email)
+	return q
+}
+
+// From matches messages with the given email address in the From field
+func (q *MessageQuery) From(email string) *MessageQuery {
+	q.setString("from", email)
+	return q
+}
+
+// CC matches messages with the given email address in the CC field
+func (q *MessageQuery) CC(email string) *MessageQuery {
+	q.setString("cc", email)
+	return q
+}
+
+// BCC matches messages with the given email address in the BCC field
+func (q *MessageQuery) BCC(email string) *MessageQuery {
+	q.setString("bcc", email)
+	return q
+}
+
+// In matches messages in the given folder or label, by ID, name or display name
+func (q *MessageQuery) In(folder string) *MessageQuery {
+	q.setString("in", folder)
+	return q
+}
+
+// Unread matches messages by their unread state
+func (q *MessageQuery) Unread(unread bool) *MessageQuery {
+	q.setBool("unread", unread)
+	return q
+}
+
+// Starred matches messages by their starred state
+func (q *MessageQuery) Starred(starred bool) *MessageQuery {
+	q.setBool("starred", starred)
+	return q
+}
+
+// Filename matches messages with an attachment of the given file name
+func (q *MessageQuery) Filename(filename string) *MessageQuery {
+	q.setString("filename", filename)
+	return q
+}
+
+// ReceivedBefore matches messages received before the given timestamp
+func (q *MessageQuery) ReceivedBefore(t Timestamp) *MessageQuery {
+	q.setTimestamp("received_before", t)
+	return q
+}
+
+// ReceivedAfter matches messages received after the given timestamp
+func (q *MessageQuery) ReceivedAfter(t Timestamp) *MessageQuery {
+	q.setTimestamp("received_after", t)
+	return q
+}
+
+// ReceivedBetween matches messages received between the given timestamps
+func (q *MessageQuery) ReceivedBetween(after Timestamp, before Timestamp) *MessageQuery {
+	return q.ReceivedAfter(after).ReceivedBefore(before)
+}
+
+// Expanded requests the expanded view of messages, which includes their Message-Id, In-Reply-To and References headers
+func (q *MessageQuery) Expanded() *MessageQuery {
+	q.setString("view", "expanded")
+	return q
+}
+
+// Offset skips the given number of messages
+func (q *MessageQuery) Offset(offset int) *MessageQuery {
+	q.setInt("offset", offset)
+	return q
+}
+
+// Limit caps the number of messages returned
+func (q *MessageQuery) Limit(limit int) *MessageQuery {
+	q.setInt("limit", limit)
+	return q
+}
+
+// Encode returns the URL encoded form of the query
+func (q *MessageQuery) Encode() string {
+	if q == nil {
+		return ""
+	}
+
+	return q.values.Encode()
+}
+
+func (q *MessageQuery) clone() *MessageQuery {
+	if q == nil {
+		return NewMessageQuery()
+	}
+
+	return &MessageQuery{query: q.query.clone()}
+}
@@ -0,0 +1,87 @@
+package gosyncengine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestThreadQueryEncode(t *testing.T) {
+	after := NewTimestamp(time.Unix(1500437314, 0))
+	query := NewThreadQuery().
+		Subject("Hello & welcome").
+		AnyEmail("a@b.com", "c+d@e.com").
+		In("inbox").
+		Unread(true).
+		LastMessageAfter(after).
+		Limit(10)
+
+	values, err := url.ParseQuery(query.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"subject":            "Hello & welcome",
+		"any_email":          "a@b.com,c+d@e.com",
+		"in":                 "inbox",
+		"unread":             "true",
+		"last_message_after": "1500437314",
+		"limit":              "10",
+	}
+
+	for key, value := range expected {
+		if values.Get(key) != value {
+			t.Errorf("Expected %s=%s, got %s", key, value, values.Get(key))
+		}
+	}
+
+	between := NewMessageQuery().ReceivedBetween(Timestamp(1500000000), Timestamp(1500086400))
+	if between.Encode() != "received_after=1500000000&received_before=1500086400" {
+		t.Errorf("Unexpected range encoding: %s", between.Encode())
+	}
+
+	var nilQuery *ThreadQuery
+	if nilQuery.Encode() != "" {
+		t.Error("A nil query should encode to an empty string")
+	}
+}
+
+func TestQueryZeroValue(t *testing.T) {
+	var threadQuery ThreadQuery
+	threadQuery.Subject("Hello")
+
+	if encoded := threadQuery.Encode(); encoded != "subject=Hello" {
+		t.Errorf("Unexpected zero value ThreadQuery encoding: %s", encoded)
+	}
+
+	if encoded := (&MessageQuery{}).Limit(3).Encode(); encoded != "limit=3" {
+		t.Errorf("Unexpected zero value MessageQuery encoding: %s", encoded)
+	}
+}
+
+func TestGetThreadMessagesWithQuery(t *testing.T) {
+	var received url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.URL.Query()
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	query := NewMessageQuery().From("a@b.com").Starred(false)
+
+	client := New(server.URL)
+	if _, err := client.GetThreadMessagesWithQuery("aaa", "id&with=chars", query); err != nil {
+		t.Fatal(err)
+	}
+
+	if received.Get("thread_id") != "id&with=chars" || received.Get("from") != "a@b.com" || received.Get("starred") != "false" {
+		t.Errorf("Unexpected query parameters: %v", received)
+	}
+
+	if query.Encode() != "from=a%40b.com&starred=false" {
+		t.Errorf("The caller's query should not be modified, got %s", query.Encode())
+	}
+}
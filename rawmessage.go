@@ -0,0 +1,109 @@
+package gosyncengine
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+)
+
+// RawMessage is a message parsed from its original RFC 822 source
+type RawMessage struct {
+	Header mail.Header
+	Parts  []RawMessagePart
+}
+
+// RawMessagePart is a single (non multipart) part of a message body with its transfer encoding removed
+type RawMessagePart struct {
+	Header      textproto.MIMEHeader
+	ContentType string
+	Filename    string
+	Body        []byte
+}
+
+// ParseRawMessage parses an RFC 822 message, flattening nested multipart bodies into their leaf parts
+func ParseRawMessage(r io.Reader) (*RawMessage, error) {
+	var msg *mail.Message
+	var err error
+
+	if msg, err = mail.ReadMessage(r); err != nil {
+		return nil, fmt.Errorf("Message parsing failed. Reason: %s", err)
+	}
+
+	var result = &RawMessage{
+		Header: msg.Header,
+	}
+
+	if result.Parts, err = parseMessageParts(textproto.MIMEHeader(msg.Header), msg.Body); err != nil {
+		return nil, fmt.Errorf("Message body parsing failed. Reason: %s", err)
+	}
+
+	return result, nil
+}
+
+func parseMessageParts(header textproto.MIMEHeader, body io.Reader) ([]RawMessagePart, error) {
+	var contentType = header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "text/plain"
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+		params = map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		var parts []RawMessagePart
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				return parts, nil
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			subParts, err := parseMessageParts(part.Header, part)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, subParts...)
+		}
+	}
+
+	data, err := ioutil.ReadAll(decodeTransferEncoding(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return nil, err
+	}
+
+	var filename = params["name"]
+	if _, dispositionParams, err := mime.ParseMediaType(header.Get("Content-Disposition")); err == nil && dispositionParams["filename"] != "" {
+		filename = dispositionParams["filename"]
+	}
+
+	return []RawMessagePart{{
+		Header:      header,
+		ContentType: mediaType,
+		Filename:    filename,
+		Body:        data,
+	}}, nil
+}
+
+func decodeTransferEncoding(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	}
+
+	return body
+}
@@ -0,0 +1,119 @@
+package gosyncengine
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testRawMessage = "From: Team <mail-noreply@somewhere.com>\r\n" +
+	"To: a b <a@b.com>\r\n" +
+	"Subject: The best Email. Ever.\r\n" +
+	"Message-Id: <1234@somewhere.com>\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=\"outer\"\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=\"inner\"\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Hello =3D World\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"\r\n" +
+	"<html><body>Hello World</body></html>\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: text/plain; name=\"notes.txt\"\r\n" +
+	"Content-Disposition: attachment; filename=\"notes.txt\"\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"bm90ZXM=\r\n" +
+	"--outer--\r\n"
+
+func TestParseRawMessage(t *testing.T) {
+	msg, err := ParseRawMessage(strings.NewReader(testRawMessage))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Header.Get("Message-Id") != "<1234@somewhere.com>" {
+		t.Errorf("Unexpected Message-Id header: %s", msg.Header.Get("Message-Id"))
+	}
+
+	if len(msg.Parts) != 3 {
+		t.Fatalf("Expected 3 parts, got %d", len(msg.Parts))
+	}
+
+	if msg.Parts[0].ContentType != "text/plain" || string(msg.Parts[0].Body) != "Hello = World" {
+		t.Errorf("Unexpected text part: %s %q", msg.Parts[0].ContentType, msg.Parts[0].Body)
+	}
+
+	if msg.Parts[1].ContentType != "text/html" {
+		t.Errorf("Unexpected html part: %s", msg.Parts[1].ContentType)
+	}
+
+	if msg.Parts[2].Filename != "notes.txt" || string(msg.Parts[2].Body) != "notes" {
+		t.Errorf("Unexpected attachment part: %s %q", msg.Parts[2].Filename, msg.Parts[2].Body)
+	}
+}
+
+func TestGetParsedRawMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/messages/aaa" || r.Header.Get("Accept") != "message/rfc822" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(testRawMessage))
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+
+	msg, err := client.GetParsedRawMessage("zzz", "aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Header.Get("Subject") != "The best Email. Ever." {
+		t.Errorf("Unexpected Subject header: %s", msg.Header.Get("Subject"))
+	}
+
+	if _, err = client.GetRawMessage("zzz", "bbb"); !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v", err)
+	}
+}
+
+func TestGetRawMessageSlowTransfer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testRawMessage[:10]))
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(testRawMessage[10:]))
+	}))
+	defer server.Close()
+
+	// The client timeout must not cut transfers short
+	client := New(server.URL, WithTimeout(50*time.Millisecond))
+
+	body, err := client.GetRawMessage("zzz", "aaa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer body.Close()
+
+	var data []byte
+	if data, err = ioutil.ReadAll(body); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != testRawMessage {
+		t.Errorf("Unexpected GetRawMessage result: %q", data)
+	}
+}
@@ -0,0 +1,182 @@
+package gosyncengine
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how calls failing with transient errors are retried.
+// Only idempotent requests (GET, PUT, DELETE, HEAD and OPTIONS) are retried,
+// so calls such as sending a message are never issued twice.
+// Backoff settings left at zero use the values of DefaultRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts, including delays requested by a Retry-After header
+	MaxBackoff time.Duration
+	// Multiplier is the growth factor of the backoff between attempts
+	Multiplier float64
+	// Jitter is the fraction (0-1) of each backoff delay that is randomized
+	Jitter float64
+	// OnAttempt, if set, is called after every attempt
+	OnAttempt func(RetryAttempt)
+}
+
+// RetryAttempt describes the outcome of a single attempt of a call
+type RetryAttempt struct {
+	Attempt    int
+	Method     string
+	Path       string
+	StatusCode int
+	Err        error
+	// Retrying is true if another attempt will be made after Delay
+	Retrying bool
+	Delay    time.Duration
+}
+
+// DefaultRetryPolicy is a reasonable policy for riding out sync engine restarts
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     30 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// WithRetryPolicy sets the retry policy applied to all calls
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(api *SyncEngineAPI) {
+		api.retryPolicy = policy
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func (p RetryPolicy) attempts(method string) int {
+	if p.MaxAttempts < 2 || !isIdempotent(method) {
+		return 1
+	}
+
+	return p.MaxAttempts
+}
+
+func (p RetryPolicy) shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// A canceled or expired context is final
+		return ctx.Err() == nil
+	}
+
+	return isRetryableStatus(resp.StatusCode)
+}
+
+// withDefaults fills the backoff settings left at zero from DefaultRetryPolicy
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultRetryPolicy.InitialBackoff
+	}
+
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultRetryPolicy.MaxBackoff
+	}
+
+	if p.Multiplier <= 0 {
+		p.Multiplier = DefaultRetryPolicy.Multiplier
+	}
+
+	return p
+}
+
+// backoff returns the delay before the attempt following the given one
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	p = p.withDefaults()
+
+	if resp != nil {
+		if delay, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			if delay > p.MaxBackoff {
+				delay = p.MaxBackoff
+			}
+			return delay
+		}
+	}
+
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < attempt; i++ {
+		delay *= p.Multiplier
+		if delay > float64(p.MaxBackoff) {
+			break
+		}
+	}
+
+	if delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		delay -= delay * p.Jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// retryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+
+	return 0, false
+}
+
+func (p RetryPolicy) observe(attempt RetryAttempt) {
+	if p.OnAttempt != nil {
+		p.OnAttempt(attempt)
+	}
+}
+
+// discardResponse drains and closes the body of a response that is being retried
+// so that the underlying connection can be reused
+func discardResponse(resp *http.Response) {
+	io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,116 @@
+package gosyncengine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	var attempts []RetryAttempt
+	policy := testRetryPolicy
+	policy.OnAttempt = func(attempt RetryAttempt) {
+		attempts = append(attempts, attempt)
+	}
+
+	client := New(server.URL, WithRetryPolicy(policy))
+	if _, err := client.GetThreads("aaa"); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 3 || len(attempts) != 3 {
+		t.Fatalf("Expected 3 attempts, got %d calls and %d observed attempts", calls, len(attempts))
+	}
+
+	if !attempts[0].Retrying || attempts[0].StatusCode != http.StatusServiceUnavailable || attempts[2].Retrying {
+		t.Errorf("Unexpected attempts: %+v", attempts)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, WithRetryPolicy(testRetryPolicy))
+	if _, err := client.GetThreads("aaa"); !IsRetryable(err) {
+		t.Errorf("Expected a retryable error, got %v", err)
+	}
+
+	if calls != testRetryPolicy.MaxAttempts {
+		t.Errorf("Expected %d calls, got %d", testRetryPolicy.MaxAttempts, calls)
+	}
+}
+
+func TestRetrySkipsNonIdempotentRequests(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := New(server.URL, WithRetryPolicy(testRetryPolicy))
+	if _, err := client.GetDeltaLatestCursor("aaa"); err == nil {
+		t.Error("Should have gotten an error here")
+	}
+
+	if calls != 1 {
+		t.Errorf("POST requests should not be retried, got %d calls", calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	if delay, ok := retryAfter("3"); !ok || delay != 3*time.Second {
+		t.Errorf("Unexpected delay for seconds value: %v %v", delay, ok)
+	}
+
+	date := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	if delay, ok := retryAfter(date); !ok || delay <= 0 || delay > time.Minute {
+		t.Errorf("Unexpected delay for date value: %v %v", delay, ok)
+	}
+
+	if _, ok := retryAfter("soon"); ok {
+		t.Error("Invalid Retry-After values should be ignored")
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "86400")
+
+	if delay := testRetryPolicy.backoff(1, resp); delay != testRetryPolicy.MaxBackoff {
+		t.Errorf("Expected Retry-After to be capped at %v, got %v", testRetryPolicy.MaxBackoff, delay)
+	}
+
+	// Backoff settings left at zero use the defaults rather than retrying right away
+	policy := RetryPolicy{MaxAttempts: 3}
+	if delay := policy.backoff(1, nil); delay != DefaultRetryPolicy.InitialBackoff {
+		t.Errorf("Expected the default initial backoff, got %v", delay)
+	}
+
+	if delay := policy.backoff(10, resp); delay != DefaultRetryPolicy.MaxBackoff {
+		t.Errorf("Expected the default maximum backoff, got %v", delay)
+	}
+}
@@ -0,0 +1,353 @@
+package gosyncengine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChangeHandler is called by a Syncer for every delta of a synced account. Returning an error stops
+// the consumer of that account, which restarts from its last checkpointed cursor after a delay.
+type ChangeHandler func(ctx context.Context, accountID string, delta Delta) error
+
+// Syncer keeps every account of the sync engine in sync: it discovers accounts, runs a delta
+// consumer per account and dispatches the deltas to a handler. Cursors are checkpointed in
+// a CursorStore once the handler processed a whole page of deltas (at-least-once delivery).
+// A failing account does not affect the others.
+type Syncer struct {
+	// Concurrency bounds the number of accounts fetching and handling deltas at the same time (defaults to 10)
+	Concurrency int
+	// MaxLongPolls bounds the number of long polls waiting for changes at the same time (defaults to 100).
+	// Accounts beyond it take turns waiting, each for up to PollTimeout.
+	MaxLongPolls int
+	// DiscoveryInterval is how often accounts are listed to add and remove consumers (defaults to 1 minute)
+	DiscoveryInterval time.Duration
+	// PollTimeout is the long poll timeout used while waiting for changes (defaults to 30 seconds)
+	PollTimeout time.Duration
+	// RetryDelay is the delay before restarting the consumer of an account after an error (defaults to 10 seconds)
+	RetryDelay time.Duration
+	// Options controls which deltas are dispatched to the handler
+	Options DeltaOptions
+	// AccountFilter, if set, selects the accounts to sync
+	AccountFilter func(Account) bool
+	// OnError, if set, is called with the errors of account consumers and of account discovery
+	// (with an empty account ID)
+	OnError func(accountID string, err error)
+
+	api     *SyncEngineAPI
+	store   CursorStore
+	handler ChangeHandler
+
+	// Settings resolved by Run, with defaults applied
+	discoveryInterval time.Duration
+	pollTimeout       time.Duration
+	retryDelay        time.Duration
+
+	mu        sync.Mutex
+	consumers map[string]*syncConsumer
+	wg        sync.WaitGroup
+	workers   chan struct{}
+	polls     chan struct{}
+}
+
+// syncConsumer tracks the consumer of a single account. Consumers of removed accounts are kept
+// until they exit, so that a consumer for the same account never overlaps them.
+type syncConsumer struct {
+	cancel  context.CancelFunc
+	done    chan struct{}
+	removed bool
+}
+
+// NewSyncer creates a Syncer dispatching the deltas of all accounts to handler and checkpointing cursors in store
+func NewSyncer(api *SyncEngineAPI, store CursorStore, handler ChangeHandler) *Syncer {
+	return &Syncer{
+		api:       api,
+		store:     store,
+		handler:   handler,
+		consumers: map[string]*syncConsumer{},
+	}
+}
+
+// Run syncs accounts until the context is canceled, then waits for all consumers to stop and returns the context's error.
+// Settings that are not positive use their defaults.
+func (s *Syncer) Run(ctx context.Context) error {
+	concurrency := s.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+
+	maxLongPolls := s.MaxLongPolls
+	if maxLongPolls <= 0 {
+		maxLongPolls = 100
+	}
+
+	s.discoveryInterval = s.DiscoveryInterval
+	if s.discoveryInterval <= 0 {
+		s.discoveryInterval = time.Minute
+	}
+
+	s.pollTimeout = s.PollTimeout
+	if s.pollTimeout <= 0 {
+		s.pollTimeout = 30 * time.Second
+	}
+
+	s.retryDelay = s.RetryDelay
+	if s.retryDelay <= 0 {
+		s.retryDelay = 10 * time.Second
+	}
+
+	s.workers = make(chan struct{}, concurrency)
+	s.polls = make(chan struct{}, maxLongPolls)
+
+	ticker := time.NewTicker(s.discoveryInterval)
+	defer ticker.Stop()
+
+	for {
+		s.discover(ctx)
+
+		select {
+		case <-ctx.Done():
+			s.wg.Wait()
+
+			s.mu.Lock()
+			s.consumers = map[string]*syncConsumer{}
+			s.mu.Unlock()
+
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Accounts returns the IDs of the accounts currently being synced
+func (s *Syncer) Accounts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	accountIDs := make([]string, 0, len(s.consumers))
+	for accountID, consumer := range s.consumers {
+		if !consumer.removed {
+			accountIDs = append(accountIDs, accountID)
+		}
+	}
+	sort.Strings(accountIDs)
+
+	return accountIDs
+}
+
+// discover starts consumers for new accounts and stops the consumers of removed accounts
+func (s *Syncer) discover(ctx context.Context) {
+	accounts, err := s.api.GetAccountsContext(ctx)
+	if err != nil {
+		// Keep the current consumers running until accounts can be listed again
+		if ctx.Err() == nil {
+			s.reportError("", err)
+		}
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := map[string]bool{}
+	for _, account := range accounts {
+		if s.AccountFilter != nil && !s.AccountFilter(account) {
+			continue
+		}
+
+		accountID := account.apiID()
+		seen[accountID] = true
+		previous, ok := s.consumers[accountID]
+		if ok && !previous.removed {
+			continue
+		}
+
+		s.start(ctx, accountID, previous)
+	}
+
+	for accountID, consumer := range s.consumers {
+		if seen[accountID] {
+			continue
+		}
+
+		if !consumer.removed {
+			consumer.cancel()
+			consumer.removed = true
+		}
+
+		select {
+		case <-consumer.done:
+			delete(s.consumers, accountID)
+		default:
+		}
+	}
+}
+
+// start runs a new consumer for the account. If the account was removed and its previous consumer
+// is still stopping, the new consumer waits for it to exit first.
+func (s *Syncer) start(ctx context.Context, accountID string, previous *syncConsumer) {
+	consumerCtx, cancel := context.WithCancel(ctx)
+	consumer := &syncConsumer{cancel: cancel, done: make(chan struct{})}
+	s.consumers[accountID] = consumer
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(consumer.done)
+
+		if previous != nil {
+			select {
+			case <-previous.done:
+			case <-consumerCtx.Done():
+				return
+			}
+		}
+
+		s.consume(consumerCtx, accountID)
+	}()
+}
+
+// consume syncs a single account, restarting after errors until the context is canceled
+func (s *Syncer) consume(ctx context.Context, accountID string) {
+	for {
+		err := s.syncAccount(ctx, accountID)
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.reportError(accountID, err)
+
+		if sleepContext(ctx, s.retryDelay) != nil {
+			return
+		}
+	}
+}
+
+func (s *Syncer) syncAccount(ctx context.Context, accountID string) error {
+	var cursor string
+	var err error
+
+	for {
+		if cursor, err = s.poll(ctx, accountID, cursor); err != nil {
+			return err
+		}
+	}
+}
+
+// poll runs a single sync cycle of the account: it handles the pending deltas after cursor, then waits
+// for new ones with a long poll and handles them. Fetching and handling deltas holds a worker slot,
+// while waiting holds a long poll slot. It returns the cursor it caught up to.
+func (s *Syncer) poll(ctx context.Context, accountID string, cursor string) (string, error) {
+	var err error
+	if cursor, err = s.catchUp(ctx, accountID, cursor); err != nil {
+		return cursor, err
+	}
+
+	var deltas *Deltas
+	if deltas, err = s.longPoll(ctx, accountID, cursor); err != nil {
+		return cursor, err
+	}
+
+	if len(deltas.Deltas) == 0 || deltas.CursorEnd == "" || deltas.CursorEnd == cursor {
+		return cursor, nil
+	}
+
+	if err = acquire(ctx, s.workers); err != nil {
+		return cursor, err
+	}
+	defer release(s.workers)
+
+	if err = s.handle(ctx, accountID, deltas); err != nil {
+		return cursor, err
+	}
+
+	if err = s.store.Save(accountID, deltas.CursorEnd); err != nil {
+		return cursor, err
+	}
+
+	return deltas.CursorEnd, nil
+}
+
+// catchUp loads the cursor of the account when it is not known yet and handles all the pending deltas after it
+func (s *Syncer) catchUp(ctx context.Context, accountID string, cursor string) (string, error) {
+	var err error
+	if err = acquire(ctx, s.workers); err != nil {
+		return cursor, err
+	}
+	defer release(s.workers)
+
+	if cursor == "" {
+		if cursor, err = s.api.loadCursor(ctx, accountID, s.store); err != nil {
+			return cursor, err
+		}
+	}
+
+	return s.drain(ctx, accountID, cursor)
+}
+
+// longPoll waits for changes after cursor
+func (s *Syncer) longPoll(ctx context.Context, accountID string, cursor string) (*Deltas, error) {
+	if err := acquire(ctx, s.polls); err != nil {
+		return nil, err
+	}
+	defer release(s.polls)
+
+	return s.api.LongPollDeltaWithOptions(ctx, accountID, cursor, s.pollTimeout, s.Options)
+}
+
+// drain handles all the pending delta pages after cursor and returns the cursor it caught up to
+func (s *Syncer) drain(ctx context.Context, accountID string, cursor string) (string, error) {
+	it := s.api.IterateDelta(ctx, accountID, cursor, s.Options)
+	it.store = s.store
+
+	for it.Next() {
+		if err := s.handle(ctx, accountID, it.Delta()); err != nil {
+			return cursor, err
+		}
+
+		if err := it.Ack(); err != nil {
+			return cursor, err
+		}
+		cursor = it.Cursor()
+	}
+
+	return cursor, it.Err()
+}
+
+func (s *Syncer) handle(ctx context.Context, accountID string, deltas *Deltas) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Handler panicked. Reason: %v", r)
+		}
+	}()
+
+	for _, delta := range deltas.Deltas {
+		if err = s.handler(ctx, accountID, delta); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// acquire takes a slot of the given pool, waiting until one is free
+func acquire(ctx context.Context, slots chan struct{}) error {
+	select {
+	case slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func release(slots chan struct{}) {
+	<-slots
+}
+
+func (s *Syncer) reportError(accountID string, err error) {
+	if s.OnError != nil {
+		s.OnError(accountID, err)
+	}
+}
@@ -0,0 +1,371 @@
+package gosyncengine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncer(t *testing.T) {
+	var mu sync.Mutex
+	accounts := Accounts{{ID: "good"}, {ID: "bad"}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountID, _, _ := r.BasicAuth()
+		cursor := r.URL.Query().Get("cursor")
+		result := Deltas{CursorStart: cursor, CursorEnd: cursor, Deltas: []Delta{}}
+
+		switch r.URL.Path {
+		case "/accounts":
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err := json.NewEncoder(w).Encode(accounts); err != nil {
+				t.Error(err)
+			}
+			return
+		case "/delta":
+			if cursor == "c0" {
+				result.Deltas = append(result.Deltas, Delta{Cursor: "c1", Event: DeltaEventDelete, Object: ObjectMessage, ID: accountID + "-1"})
+				result.CursorEnd = "c1"
+			}
+		case "/delta/longpoll":
+			time.Sleep(10 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	store := NewMemoryCursorStore()
+	store.Save("good", "c0")
+	store.Save("bad", "c0")
+
+	var handled []string
+	var failures int
+	syncer := NewSyncer(New(server.URL), store, func(ctx context.Context, accountID string, delta Delta) error {
+		if accountID == "bad" {
+			return errors.New("handler failed")
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, delta.ID)
+
+		return nil
+	})
+	syncer.Concurrency = 1
+	syncer.DiscoveryInterval = 20 * time.Millisecond
+	syncer.RetryDelay = 10 * time.Millisecond
+	syncer.OnError = func(accountID string, err error) {
+		if accountID != "bad" {
+			t.Errorf("Unexpected error for account '%s': %v", accountID, err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		failures++
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- syncer.Run(ctx)
+	}()
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handled) == 1 && failures >= 2
+	})
+
+	if handled[0] != "good-1" {
+		t.Errorf("Unexpected handled deltas: %v", handled)
+	}
+
+	if cursor, _ := store.Load("good"); cursor != "c1" {
+		t.Errorf("Expected cursor 'c1' for 'good', got '%s'", cursor)
+	}
+
+	if cursor, _ := store.Load("bad"); cursor != "c0" {
+		t.Errorf("Expected cursor 'c0' for 'bad', got '%s'", cursor)
+	}
+
+	mu.Lock()
+	accounts = Accounts{{ID: "good"}}
+	mu.Unlock()
+
+	waitFor(t, func() bool {
+		return reflect.DeepEqual(syncer.Accounts(), []string{"good"})
+	})
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Syncer did not stop")
+	}
+}
+
+func TestSyncerBoundsLongPolls(t *testing.T) {
+	var mu sync.Mutex
+	var active, maxActive int
+	polled := map[string]bool{}
+	handled := map[string]bool{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountID, _, _ := r.BasicAuth()
+		cursor := r.URL.Query().Get("cursor")
+		result := Deltas{CursorStart: cursor, CursorEnd: cursor, Deltas: []Delta{}}
+
+		switch r.URL.Path {
+		case "/accounts":
+			w.Write([]byte(`[{"id": "a1"}, {"id": "a2"}, {"id": "a3"}]`))
+			return
+		case "/delta":
+			if cursor == "c0" {
+				result.Deltas = append(result.Deltas, Delta{Cursor: "c1", Event: DeltaEventDelete, Object: ObjectMessage, ID: accountID})
+				result.CursorEnd = "c1"
+			}
+		case "/delta/longpoll":
+			mu.Lock()
+			active++
+			if active > maxActive {
+				maxActive = active
+			}
+			polled[accountID] = true
+			mu.Unlock()
+
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+
+			mu.Lock()
+			active--
+			mu.Unlock()
+		}
+
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	store := NewMemoryCursorStore()
+	for _, accountID := range []string{"a1", "a2", "a3"} {
+		store.Save(accountID, "c0")
+	}
+
+	syncer := NewSyncer(New(server.URL), store, func(ctx context.Context, accountID string, delta Delta) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled[accountID] = true
+
+		return nil
+	})
+	syncer.Concurrency = 1
+	syncer.MaxLongPolls = 2
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- syncer.Run(ctx)
+	}()
+
+	// Pending deltas are handled without waiting for the long polls of other accounts
+	start := time.Now()
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handled) == 3
+	})
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Handling pending deltas took %v", elapsed)
+	}
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(polled) == 3
+	})
+
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxActive > 2 {
+		t.Errorf("Expected at most 2 concurrent long polls, got %d", maxActive)
+	}
+}
+
+func TestSyncerReaddedAccount(t *testing.T) {
+	var mu sync.Mutex
+	accounts := Accounts{{ID: "aaa"}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		result := Deltas{CursorStart: cursor, CursorEnd: cursor, Deltas: []Delta{}}
+
+		switch r.URL.Path {
+		case "/accounts":
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err := json.NewEncoder(w).Encode(accounts); err != nil {
+				t.Error(err)
+			}
+			return
+		case "/delta":
+			if cursor == "c0" {
+				result.Deltas = append(result.Deltas, Delta{Cursor: "c1", Event: DeltaEventDelete, Object: ObjectMessage, ID: "mmm"})
+				result.CursorEnd = "c1"
+			}
+		case "/delta/longpoll":
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	store := NewMemoryCursorStore()
+	store.Save("aaa", "c0")
+
+	release := make(chan struct{})
+	var calls, running, maxRunning int
+	syncer := NewSyncer(New(server.URL), store, func(ctx context.Context, accountID string, delta Delta) error {
+		mu.Lock()
+		calls++
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+
+		// A slow handler that ignores cancellation
+		<-release
+
+		return ctx.Err()
+	})
+	syncer.DiscoveryInterval = 10 * time.Millisecond
+	syncer.RetryDelay = 10 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- syncer.Run(ctx)
+	}()
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls == 1
+	})
+
+	mu.Lock()
+	accounts = Accounts{}
+	mu.Unlock()
+
+	waitFor(t, func() bool {
+		return len(syncer.Accounts()) == 0
+	})
+
+	mu.Lock()
+	accounts = Accounts{{ID: "aaa"}}
+	mu.Unlock()
+
+	waitFor(t, func() bool {
+		return reflect.DeepEqual(syncer.Accounts(), []string{"aaa"})
+	})
+
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls == 2
+	})
+
+	cancel()
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	if maxRunning != 1 {
+		t.Errorf("Expected the handler to never run concurrently for an account, got %d", maxRunning)
+	}
+
+	if cursor, _ := store.Load("aaa"); cursor != "c1" {
+		t.Errorf("Expected cursor 'c1', got '%s'", cursor)
+	}
+}
+
+// waitFor polls condition until it holds or the test times out
+func waitFor(t *testing.T, condition func() bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Condition not met in time")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSyncerDefaults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	syncer := NewSyncer(New(server.URL), NewMemoryCursorStore(), func(ctx context.Context, accountID string, delta Delta) error {
+		return nil
+	})
+	syncer.DiscoveryInterval = 0
+	syncer.RetryDelay = -time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := syncer.Run(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	if syncer.discoveryInterval != time.Minute || syncer.pollTimeout != 30*time.Second || syncer.retryDelay != 10*time.Second {
+		t.Errorf("Unexpected defaults: %v %v %v", syncer.discoveryInterval, syncer.pollTimeout, syncer.retryDelay)
+	}
+
+	if cap(syncer.workers) != 10 || cap(syncer.polls) != 100 {
+		t.Errorf("Unexpected pool sizes: %d %d", cap(syncer.workers), cap(syncer.polls))
+	}
+
+	// The caller's settings are left untouched
+	if syncer.Concurrency != 0 || syncer.DiscoveryInterval != 0 || syncer.RetryDelay != -time.Second {
+		t.Errorf("Run changed the settings: %d %v %v", syncer.Concurrency, syncer.DiscoveryInterval, syncer.RetryDelay)
+	}
+}
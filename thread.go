@@ -1,17 +1,20 @@
 package gosyncengine
 
+import "encoding/json"
+
 // Thread contains information on a single thread
 type Thread struct {
 	ID                           string        `json:"id"`
 	AccountID                    string        `json:"account_id"`
 	Object                       string        `json:"object"`
 	DraftIDs                     []string      `json:"draft_ids"`
-	FirstMessageTimestamp        int           `json:"first_message_timestamp"`
+	FirstMessageTimestamp        Timestamp     `json:"first_message_timestamp"`
 	Folders                      []Folder      `json:"folders"`
 	HasAttachments               bool          `json:"has_attachments"`
-	LastMessageReceivedTimestamp int           `json:"last_message_received_timestamp"`
-	LastMessageSentTimestamp     int           `json:"last_message_sent_timestamp"`
-	LastMessageTimestamp         int           `json:"last_message_timestamp"`
+	LastMessageReceivedTimestamp Timestamp     `json:"last_message_received_timestamp"`
+	LastMessageSentTimestamp     Timestamp     `json:"last_message_sent_timestamp"`
+	Labels                       []Label       `json:"labels"`
+	LastMessageTimestamp         Timestamp     `json:"last_message_timestamp"`
 	MessageIDs                   []string      `json:"message_ids"`
 	Participants                 []Participant `json:"participants"`
 	Snippet                      string        `json:"snippet"`
@@ -23,3 +26,27 @@ type Thread struct {
 
 // Threads is a collection of Thread objects
 type Threads []Thread
+
+// ThreadUpdate describes the changes to apply to a thread. Nil fields are left unchanged,
+// while an empty LabelIDs removes all the labels of the thread.
+type ThreadUpdate struct {
+	Unread   *bool    `json:"unread,omitempty"`
+	Starred  *bool    `json:"starred,omitempty"`
+	FolderID *string  `json:"folder_id,omitempty"`
+	LabelIDs []string `json:"label_ids,omitempty"`
+}
+
+// MarshalJSON sends label_ids whenever LabelIDs is not nil, so that an empty list clears the labels
+func (u ThreadUpdate) MarshalJSON() ([]byte, error) {
+	type threadUpdate ThreadUpdate
+	var body = struct {
+		threadUpdate
+		LabelIDs *[]string `json:"label_ids,omitempty"`
+	}{threadUpdate: threadUpdate(u)}
+
+	if u.LabelIDs != nil {
+		body.LabelIDs = &u.LabelIDs
+	}
+
+	return json.Marshal(body)
+}
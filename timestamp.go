@@ -0,0 +1,43 @@
+package gosyncengine
+
+import (
+	"strconv"
+	"time"
+)
+
+// Timestamp is a point in time, encoded by the sync engine as seconds since the unix epoch
+type Timestamp int64
+
+// NewTimestamp returns the Timestamp of the given time, truncated to the second
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp(t.Unix())
+}
+
+// Time returns the timestamp as a time.Time in the local time zone
+func (t Timestamp) Time() time.Time {
+	return time.Unix(int64(t), 0)
+}
+
+// IsZero returns true if the timestamp is not set
+func (t Timestamp) IsZero() bool {
+	return t == 0
+}
+
+// Before returns true if t is before u
+func (t Timestamp) Before(u Timestamp) bool {
+	return t < u
+}
+
+// After returns true if t is after u
+func (t Timestamp) After(u Timestamp) bool {
+	return t > u
+}
+
+func (t Timestamp) String() string {
+	return t.Time().UTC().Format(time.RFC3339)
+}
+
+// queryValue returns the timestamp as expected by query filters
+func (t Timestamp) queryValue() string {
+	return strconv.FormatInt(int64(t), 10)
+}
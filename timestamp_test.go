@@ -0,0 +1,51 @@
+package gosyncengine
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimestampJSON(t *testing.T) {
+	var thread Thread
+	if err := json.Unmarshal([]byte(`{"first_message_timestamp": 1500437314, "last_message_sent_timestamp": null}`), &thread); err != nil {
+		t.Fatal(err)
+	}
+
+	if !thread.FirstMessageTimestamp.Time().Equal(time.Date(2017, 7, 19, 4, 8, 34, 0, time.UTC)) {
+		t.Errorf("Unexpected time: %v", thread.FirstMessageTimestamp.Time())
+	}
+
+	if !thread.LastMessageSentTimestamp.IsZero() {
+		t.Errorf("A null timestamp should be zero, got %d", thread.LastMessageSentTimestamp)
+	}
+
+	data, err := json.Marshal(Message{Date: thread.FirstMessageTimestamp})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var message map[string]interface{}
+	json.Unmarshal(data, &message)
+	if message["date"] != float64(1500437314) {
+		t.Errorf("Timestamps should encode as unix seconds, got %v", message["date"])
+	}
+}
+
+func TestTimestampCompare(t *testing.T) {
+	now := time.Now()
+	earlier := NewTimestamp(now.Add(-time.Hour))
+	later := NewTimestamp(now)
+
+	if !earlier.Before(later) || !later.After(earlier) || earlier.After(later) {
+		t.Error("Unexpected timestamp comparison result")
+	}
+
+	if later.Time().Unix() != now.Unix() {
+		t.Errorf("Unexpected time: %v", later.Time())
+	}
+
+	if Timestamp(0).String() != "1970-01-01T00:00:00Z" {
+		t.Errorf("Unexpected string: %s", Timestamp(0))
+	}
+}
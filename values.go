@@ -0,0 +1,11 @@
+package gosyncengine
+
+// Bool returns a pointer to the given value, for use in optional update fields
+func Bool(value bool) *bool {
+	return &value
+}
+
+// String returns a pointer to the given value, for use in optional update fields
+func String(value string) *string {
+	return &value
+}